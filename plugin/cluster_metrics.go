@@ -0,0 +1,89 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+// clusterKeys are keys returning cluster-wide data, which doesn't depend on the database a connection is made to.
+var clusterKeys = map[string]bool{
+	keyActivityLongActiveCount:         true,
+	keyArchiveSize:                     true,
+	keyArchiveLagSec:                   true,
+	keyArchiveReadyCount:               true,
+	keyAutovacuum:                      true,
+	keyBackendsOldestQueryAge:          true,
+	keyBgwriter:                        true,
+	keyCache:                           true,
+	keyConnections:                     true,
+	keyConnectionsActive:               true,
+	keyConnectionsAvailable:            true,
+	keyConnectionsByUser:               true,
+	keyConnectionsIdle:                 true,
+	keyConnectionsIdleInTransaction:    true,
+	keyConnectionsIdleInTxMaxAge:       true,
+	keyDBStat:                          true,
+	keyDBStatByDB:                      true,
+	keyDBStatSum:                       true,
+	keyDatabaseAgeAll:                  true,
+	keyDatabasesDiscovery:              true,
+	keyDatabasesDiscoveryExt:           true,
+	keyHBARules:                        true,
+	keyHealth:                          true,
+	keyLocks:                           true,
+	keyLocksByMode:                     true,
+	keyLocksMaxWait:                    true,
+	keyOldestXid:                       true,
+	keyQueries:                         true,
+	keyQueryCancel:                     true,
+	keyReplicationCount:                true,
+	keyReplicationCountByState:         true,
+	keyReplicationFlushLagSec:          true,
+	keyReplicationLagB:                 true,
+	keyReplicationLagByStandby:         true,
+	keyReplicationLagSec:               true,
+	keyReplicationPaused:               true,
+	keyReplicationProcessInfo:          true,
+	keyReplicationProcessNameDiscovery: true,
+	keyReplicationRecoveryRole:         true,
+	keyReplicationReplayLagSec:         true,
+	keyReplicationSlotsWalStatus:       true,
+	keyReplicationStatus:               true,
+	keyReplicationSyncState:            true,
+	keyReplicationWriteLagSec:          true,
+	keyRoleGrants:                      true,
+	keyRoles:                           true,
+	keyStandbyFeedback:                 true,
+	keyStatProgressBasebackup:          true,
+	keyStatResetTime:                   true,
+	keyStatSLRU:                        true,
+	keyTxidCurrent:                     true,
+	keyUptime:                          true,
+	keyVersion:                         true,
+	keyVersionParsed:                   true,
+	keyWal:                             true,
+	keyWalConfig:                       true,
+	keyWalCount:                        true,
+	keyWalSize:                         true,
+	keyWraparoundStatus:                true,
+}
+
+// setClusterDatabase replaces the database of a cluster-wide key with the given one, so all cluster-wide keys of
+// a server share a single connection whatever database they are requested for. Nothing is changed for other keys
+// or if database is empty.
+func setClusterDatabase(key string, params map[string]string, database string) {
+	if database == "" || !clusterKeys[key] {
+		return
+	}
+
+	params[databaseParam] = database
+}
@@ -0,0 +1,65 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"testing"
+)
+
+func Test_setClusterDatabase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		key             string
+		clusterDatabase string
+		wantShared      bool
+	}{
+		{"+clusterKey", keyDBStat, "postgres", true},
+		{"+replicationKey", keyReplicationLagSec, "postgres", true},
+		{"+connectionsKey", keyConnections, "postgres", true},
+		{"+databaseKey", keyDatabaseSize, "postgres", false},
+		{"+relationKey", keyRelationSize, "postgres", false},
+		{"+disabled", keyDBStat, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			connIDs := make(map[connID]struct{})
+
+			for _, database := range []string{"app", "billing", "postgres"} {
+				params := map[string]string{
+					uriParam:      "tcp://localhost",
+					userParam:     "zabbix",
+					databaseParam: database,
+				}
+
+				setClusterDatabase(tt.key, params, tt.clusterDatabase)
+
+				ci, err := createConnID(params)
+				if err != nil {
+					t.Fatalf("createConnID() error = %v", err)
+				}
+
+				connIDs[ci] = struct{}{}
+			}
+
+			if gotShared := len(connIDs) == 1; gotShared != tt.wantShared {
+				t.Errorf("setClusterDatabase() connections shared = %v, want %v", gotShared, tt.wantShared)
+			}
+		})
+	}
+}
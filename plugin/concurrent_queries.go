@@ -0,0 +1,111 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// maxConcurrentQueries is the number of queries of a single handler running at once, so a handler doesn't take
+// more than a few connections of the pool. Each running query holds its own backend, so a single item may use up
+// to maxConcurrentQueries server connections, e.g. 2 for pgsql.archive. MaxConnections limits cached pools, not
+// backends.
+const maxConcurrentQueries = 4
+
+// runQueriesConcurrently executes independent queries returning a single value each in parallel and returns
+// their results in the order of queries. Queries share the handler context, so they are bound by its timeout, and
+// the rest of them are canceled once one fails, the error of the query failed first is returned.
+func runQueriesConcurrently(ctx context.Context, conn PostgresClient, queries ...string) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		firstErr error
+		sem      = make(chan struct{}, maxConcurrentQueries)
+		results  = make([]string, len(queries))
+	)
+
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+
+			cancel()
+		})
+	}
+
+	for i, query := range queries {
+		wg.Add(1)
+
+		go func(i int, query string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				fail(zbxerr.ErrorCannotFetchData.Wrap(ctx.Err()))
+
+				return
+			}
+
+			defer func() { <-sem }()
+
+			value, err := queryValue(ctx, conn, query)
+			if err != nil {
+				fail(err)
+
+				return
+			}
+
+			results[i] = value
+		}(i, query)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return results, nil
+}
+
+// queryValue executes a query returning a single value and returns the value if all is OK or an error otherwise.
+func queryValue(ctx context.Context, conn PostgresClient, query string) (string, error) {
+	var value string
+
+	row, err := conn.QueryRow(ctx, query)
+	if err != nil {
+		return "", zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&value)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return "", zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return "", zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,227 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+func Test_runQueriesConcurrently(t *testing.T) {
+	type mock struct {
+		query string
+		row   *sqlmock.Rows
+		err   error
+	}
+
+	tests := []struct {
+		name      string
+		mocks     []mock
+		want      []string
+		wantErr   error
+		wantCalls bool
+	}{
+		{
+			"+valid",
+			[]mock{
+				{query: "SELECT 1", row: sqlmock.NewRows([]string{"v"}).AddRow("one")},
+				{query: "SELECT 2", row: sqlmock.NewRows([]string{"v"}).AddRow("two")},
+				{query: "SELECT 3", row: sqlmock.NewRows([]string{"v"}).AddRow("three")},
+			},
+			[]string{"one", "two", "three"},
+			nil,
+			true,
+		},
+		{
+			"+noQueries",
+			nil,
+			[]string{},
+			nil,
+			true,
+		},
+		{
+			"-queryErr",
+			[]mock{
+				{query: "SELECT 1", row: sqlmock.NewRows([]string{"v"}).AddRow("one")},
+				{query: "SELECT 2", row: sqlmock.NewRows([]string{"v"}), err: errors.New("query err")},
+			},
+			nil,
+			zbxerr.ErrorCannotFetchData,
+			false,
+		},
+		{
+			"-noRows",
+			[]mock{
+				{query: "SELECT 1", row: sqlmock.NewRows([]string{"v"})},
+			},
+			nil,
+			zbxerr.ErrorEmptyResult,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.MatchExpectationsInOrder(false)
+
+			queries := make([]string, 0, len(tt.mocks))
+
+			for _, m := range tt.mocks {
+				queries = append(queries, m.query)
+
+				mock.ExpectQuery(m.query).WillReturnRows(m.row).WillReturnError(m.err)
+			}
+
+			got, err := runQueriesConcurrently(context.Background(), &PGConn{client: db}, queries...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("runQueriesConcurrently() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("runQueriesConcurrently() error = %v", err)
+			}
+
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("runQueriesConcurrently() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); tt.wantCalls && err != nil {
+				t.Fatalf(
+					"runQueriesConcurrently() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
+
+func Test_runQueriesConcurrently_canceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sql mock: %s", err.Error())
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT 1").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow("1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = runQueriesConcurrently(ctx, &PGConn{client: db}, "SELECT 1")
+	if err == nil {
+		t.Fatal("runQueriesConcurrently() error = nil, want a timeout")
+	}
+}
+
+// latencyDriver is a database driver answering each query with a single value after a fixed latency, unlike sql
+// mock it runs queries of separate connections in parallel.
+type latencyDriver struct {
+	latency time.Duration
+	values  map[string]string
+}
+
+type latencyConn struct{ d *latencyDriver }
+
+type latencyRows struct {
+	value string
+	done  bool
+}
+
+func (d *latencyDriver) Open(string) (driver.Conn, error)             { return &latencyConn{d}, nil }
+func (d *latencyDriver) Connect(context.Context) (driver.Conn, error) { return &latencyConn{d}, nil }
+func (d *latencyDriver) Driver() driver.Driver                        { return d }
+
+func (c *latencyConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *latencyConn) Close() error                        { return nil }
+func (c *latencyConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *latencyConn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	select {
+	case <-time.After(c.d.latency):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return &latencyRows{value: c.d.values[query]}, nil
+}
+
+func (r *latencyRows) Columns() []string { return []string{"v"} }
+func (r *latencyRows) Close() error      { return nil }
+
+func (r *latencyRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done = true
+	dest[0] = r.value
+
+	return nil
+}
+
+// Benchmark_archiveHandler runs the archive queries with a fixed latency one after another, as the handler did
+// before, and concurrently, as it does now, so the concurrent run is expected to take about half the time.
+func Benchmark_archiveHandler(b *testing.B) {
+	db := sql.OpenDB(&latencyDriver{
+		latency: 5 * time.Millisecond,
+		values: map[string]string{
+			archiveCountQuery: `{"archived_count":1,"failed_count":0}`,
+			archiveSizeQuery:  `{"count_files":0,"size_files":0}`,
+		},
+	})
+	defer db.Close()
+
+	conn := &PGConn{client: db}
+
+	b.Run("sequential", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			for _, query := range []string{archiveCountQuery, archiveSizeQuery} {
+				_, err := queryValue(context.Background(), conn, query)
+				if err != nil {
+					b.Fatalf("queryValue() error = %v", err)
+				}
+			}
+		}
+	})
+
+	b.Run("concurrent", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_, err := archiveHandler(context.Background(), conn, keyArchiveSize, nil)
+			if err != nil {
+				b.Fatalf("archiveHandler() error = %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,88 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+)
+
+// dnsCache caches addresses of host names for ttl, so a host is resolved once per ttl instead of once per
+// connection. Failed lookups are not cached.
+type dnsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	lookup  pgconn.LookupFunc
+	now     func() time.Time
+	entries map[string]dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// newDNSCache returns a cache resolving hosts with lookup, nil is returned if ttl is not positive.
+func newDNSCache(ttl time.Duration, lookup pgconn.LookupFunc) *dnsCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &dnsCache{
+		ttl:     ttl,
+		lookup:  lookup,
+		now:     time.Now,
+		entries: make(map[string]dnsCacheEntry),
+	}
+}
+
+// LookupHost returns cached addresses of a host or resolves it if the cached addresses have expired. IP addresses
+// are returned as is.
+func (c *dnsCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		return append([]string(nil), entry.addrs...), nil
+	}
+
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[host] = dnsCacheEntry{addrs: append([]string(nil), addrs...), expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return addrs, nil
+}
+
+// forget drops cached addresses of a host, e.g. after a failed connection, so the host is resolved again by the
+// next connection in case its addresses have changed.
+func (c *dnsCache) forget(host string) {
+	c.mu.Lock()
+	delete(c.entries, host)
+	c.mu.Unlock()
+}
@@ -0,0 +1,182 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// stubResolver returns the current addresses of hosts and counts lookups.
+type stubResolver struct {
+	mu      sync.Mutex
+	addrs   map[string][]string
+	lookups int
+}
+
+func (r *stubResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lookups++
+
+	addrs, ok := r.addrs[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return addrs, nil
+}
+
+func (r *stubResolver) set(host string, addrs ...string) {
+	r.mu.Lock()
+	r.addrs[host] = addrs
+	r.mu.Unlock()
+}
+
+func Test_newDNSCache(t *testing.T) {
+	t.Parallel()
+
+	r := &stubResolver{addrs: map[string][]string{}}
+
+	if c := newDNSCache(0, r.LookupHost); c != nil {
+		t.Errorf("newDNSCache() = %v, want nil for zero TTL", c)
+	}
+
+	if c := newDNSCache(time.Minute, r.LookupHost); c == nil {
+		t.Error("newDNSCache() = nil, want cache for positive TTL")
+	}
+}
+
+func Test_dnsCache_LookupHost(t *testing.T) {
+	t.Parallel()
+
+	r := &stubResolver{addrs: map[string][]string{"db.example": {"192.0.2.1", "2001:db8::1"}}}
+	now := time.Now()
+
+	c := newDNSCache(time.Minute, r.LookupHost)
+	c.now = func() time.Time { return now }
+
+	lookup := func(host string, wantAddrs []string, wantLookups int) {
+		t.Helper()
+
+		got, err := c.LookupHost(context.Background(), host)
+		if err != nil {
+			t.Fatalf("dnsCache.LookupHost() error = %v", err)
+		}
+
+		if diff := cmp.Diff(wantAddrs, got); diff != "" {
+			t.Fatalf("dnsCache.LookupHost() = %s", diff)
+		}
+
+		if r.lookups != wantLookups {
+			t.Fatalf("dnsCache.LookupHost() resolver lookups = %d, want %d", r.lookups, wantLookups)
+		}
+	}
+
+	lookup("db.example", []string{"192.0.2.1", "2001:db8::1"}, 1)
+
+	// Cached within TTL, even though the records rotate.
+	r.set("db.example", "192.0.2.2")
+	now = now.Add(30 * time.Second)
+	lookup("db.example", []string{"192.0.2.1", "2001:db8::1"}, 1)
+
+	// Rotated records are picked up once TTL expires.
+	now = now.Add(time.Minute)
+	lookup("db.example", []string{"192.0.2.2"}, 2)
+
+	// Forgotten host is resolved again.
+	r.set("db.example", "192.0.2.3")
+	c.forget("db.example")
+	lookup("db.example", []string{"192.0.2.3"}, 3)
+
+	// IP addresses are not resolved.
+	lookup("2001:db8::2", []string{"2001:db8::2"}, 3)
+	lookup("192.0.2.4", []string{"192.0.2.4"}, 3)
+
+	// Failed lookups are not cached.
+	for i := 0; i < 2; i++ {
+		_, err := c.LookupHost(context.Background(), "missing.example")
+
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) {
+			t.Fatalf("dnsCache.LookupHost() error = %v, want DNS error", err)
+		}
+	}
+
+	if r.lookups != 5 {
+		t.Fatalf("dnsCache.LookupHost() resolver lookups = %d, want 5", r.lookups)
+	}
+}
+
+func Test_createClient_lookup(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+
+	defer l.Close()
+
+	accepted := make(chan struct{}, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		conn.Close()
+		accepted <- struct{}{}
+	}()
+
+	r := &stubResolver{addrs: map[string][]string{"db.example": {"127.0.0.1"}}}
+	c := newDNSCache(time.Minute, r.LookupHost)
+
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+
+	client, err := createClient(
+		"host=db.example port="+port+" user=zabbix sslmode=disable", time.Second, nil, "", c.LookupHost,
+	)
+	if err != nil {
+		t.Fatalf("createClient() error = %v", err)
+	}
+
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// The listener doesn't speak the protocol, only the connection to the resolved address matters.
+	_ = client.PingContext(ctx)
+
+	select {
+	case <-accepted:
+	case <-ctx.Done():
+		t.Fatal("createClient() didn't connect to the address returned by lookup")
+	}
+
+	if r.lookups != 1 {
+		t.Errorf("createClient() resolver lookups = %d, want 1", r.lookups)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithAnalyzeProgress is the first version with pg_stat_progress_analyze.
+const pgVersionWithAnalyzeProgress = 130000
+
+// analyzeProgressQuery returns progress of ANALYZE commands, percent is calculated by sampled blocks. Relation
+// names are resolved in the connected database only.
+const analyzeProgressQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.pid), '[]')
+				FROM (
+					SELECT
+						p.pid,
+						p.datname,
+						CASE WHEN p.datname = current_database() THEN p.relid::regclass::text END AS relation,
+						p.phase,
+						p.sample_blks_total,
+						p.sample_blks_scanned,
+						CASE
+							WHEN p.sample_blks_total > 0
+								THEN round(100.0 * p.sample_blks_scanned / p.sample_blks_total, 2)
+							ELSE 0
+						END AS percent
+					  FROM pg_catalog.pg_stat_progress_analyze p
+				) T;`
+
+// analyzeProgressHandler gets progress of running ANALYZE commands and returns JSON if all is OK or nil otherwise.
+func analyzeProgressHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var progressJSON string
+
+	if conn.PostgresVersion() < pgVersionWithAnalyzeProgress {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("analyze progress requires PostgreSQL %d or newer", pgVersionWithAnalyzeProgress),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, analyzeProgressQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&progressJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(progressJSON), nil
+}
@@ -18,22 +18,28 @@ import (
 	"context"
 	"errors"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// archiveHandler gets info about count and size of archive files and returns JSON if all is OK or nil otherwise.
-func archiveHandler(ctx context.Context, conn PostgresClient,
-	_ string, _ map[string]string, _ ...string) (any, error) {
-	var archiveCountJSON, archiveSizeJSON string
+// pgVersionWithArchiveStatusDir is the first version with pg_ls_archive_statusdir().
+const pgVersionWithArchiveStatusDir = 120000
 
-	queryArchiveCount := `SELECT row_to_json(T)
+// archiveReadyCountQuery counts WAL segments waiting for the archiver, each has a .ready file in the
+// archive_status directory.
+const archiveReadyCountQuery = `SELECT count(*)
+				FROM pg_catalog.pg_ls_archive_statusdir()
+			   WHERE name LIKE '%.ready';`
+
+const archiveCountQuery = `SELECT row_to_json(T)
 							FROM (
 									SELECT archived_count, failed_count
 								   	  FROM pg_stat_archiver
 								) T;`
 
-	queryArchiveSize := `SELECT row_to_json(T)
+const archiveSizeQuery = `SELECT row_to_json(T)
 							FROM (
 								WITH values AS (
 									SELECT
@@ -53,35 +59,52 @@ func archiveHandler(ctx context.Context, conn PostgresClient,
 								FROM values
 							) T;`
 
-	row, err := conn.QueryRow(ctx, queryArchiveCount)
+// archiveHandler gets info about count and size of archive files and returns JSON if all is OK or nil otherwise.
+func archiveHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	results, err := runQueriesConcurrently(ctx, conn, archiveCountQuery, archiveSizeQuery)
 	if err != nil {
-		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+		return nil, err
 	}
 
-	err = row.Scan(&archiveCountJSON)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
-		}
+	archiveCountJSON, archiveSizeJSON := results[0], results[1]
 
-		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	result := archiveCountJSON[:len(archiveCountJSON)-1] + "," + archiveSizeJSON[1:]
+
+	return jsonResult(result), nil
+}
+
+// archiveReadyCountHandler gets the number of WAL segments waiting for the archiver if all is OK or nil otherwise.
+func archiveReadyCountHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var count int64
+
+	if conn.PostgresVersion() < pgVersionWithArchiveStatusDir {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("archive status directory listing requires PostgreSQL %d or newer", pgVersionWithArchiveStatusDir),
+		)
 	}
 
-	row, err = conn.QueryRow(ctx, queryArchiveSize)
+	row, err := conn.QueryRow(ctx, archiveReadyCountQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
-	err = row.Scan(&archiveSizeJSON)
+	err = row.Scan(&count)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
 		}
 
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == sqlStateInsufficientPrivilege {
+			return nil, zbxerr.ErrorCannotFetchData.Wrap(
+				errs.Wrap(err, "pg_ls_archive_statusdir() requires superuser or pg_monitor role"),
+			)
+		}
+
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
-	result := archiveCountJSON[:len(archiveCountJSON)-1] + "," + archiveSizeJSON[1:]
-
-	return result, nil
+	return count, nil
 }
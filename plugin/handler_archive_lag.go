@@ -0,0 +1,53 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// archiveLagSecQuery returns the time since the last WAL file was archived successfully, no rows are returned if
+// archiving has never succeeded.
+const archiveLagSecQuery = `SELECT extract(epoch FROM now() - last_archived_time)::bigint
+				FROM pg_catalog.pg_stat_archiver
+			   WHERE last_archived_time IS NOT NULL;`
+
+// archiveLagSecHandler gets the time in seconds since the last successful archiving of a WAL file if all is OK or
+// nil otherwise. An empty result error is returned if archiving has never succeeded, so OnEmptyResult applies.
+func archiveLagSecHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var lag int64
+
+	row, err := conn.QueryRow(ctx, archiveLagSecQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&lag)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return lag, nil
+}
@@ -0,0 +1,107 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+func Test_archiveLagSecHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name          string
+		onEmptyResult string
+		mock          mock
+		want          any
+		wantErr       error
+	}{
+		{
+			"+valid",
+			onEmptyResultError,
+			mock{row: sqlmock.NewRows([]string{"lag"}).AddRow(int64(42))},
+			int64(42),
+			nil,
+		},
+		{
+			"-neverArchived",
+			onEmptyResultError,
+			mock{row: sqlmock.NewRows([]string{"lag"})},
+			nil,
+			zbxerr.ErrorEmptyResult,
+		},
+		{
+			"+neverArchivedZero",
+			onEmptyResultZero,
+			mock{row: sqlmock.NewRows([]string{"lag"})},
+			0,
+			nil,
+		},
+		{
+			"-queryErr",
+			onEmptyResultZero,
+			mock{
+				row: sqlmock.NewRows([]string{"lag"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			zbxerr.ErrorCannotFetchData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM pg_catalog.pg_stat_archiver`).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := archiveLagSecHandler(context.Background(), &PGConn{client: db}, keyArchiveLagSec, nil)
+			if err != nil {
+				got, err = handleEmptyResult(err, tt.onEmptyResult)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("archiveLagSecHandler() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("archiveLagSecHandler() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("archiveLagSecHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("archiveLagSecHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
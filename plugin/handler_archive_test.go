@@ -57,9 +57,33 @@ func TestPlugin_archiveHandler(t *testing.T) {
 				return
 			}
 
-			if len(got.(string)) == 0 {
+			if len(got.(jsonResult)) == 0 {
 				t.Errorf("Plugin.archiveHandler() = %v", got)
 			}
 		})
 	}
 }
+
+func TestPlugin_archiveReadyCountHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := archiveReadyCountHandler(context.Background(), sharedPool, keyArchiveReadyCount, nil)
+	if sharedPool.PostgresVersion() < pgVersionWithArchiveStatusDir {
+		if err == nil {
+			t.Fatal("Plugin.archiveReadyCountHandler() error = nil, want unsupported metric error")
+		}
+
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("Plugin.archiveReadyCountHandler() error = %v", err)
+	}
+
+	if count, ok := got.(int64); !ok || count < 0 {
+		t.Errorf("Plugin.archiveReadyCountHandler() = %v, want a non-negative number", got)
+	}
+}
@@ -22,18 +22,18 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// autovacuumHandler returns count of autovacuum workers if all is OK or nil otherwise.
-func autovacuumHandler(ctx context.Context, conn PostgresClient,
-	_ string, _ map[string]string, _ ...string) (any, error) {
-	var countAutovacuumWorkers int64
-
-	query := `SELECT count(*)
+const autovacuumQuery = `SELECT count(*)
 				FROM pg_catalog.pg_stat_activity
 				WHERE backend_type = 'autovacuum worker'
 				 AND state <> 'idle'
 				 AND pid <> pg_catalog.pg_backend_pid()`
 
-	row, err := conn.QueryRow(ctx, query)
+// autovacuumHandler returns count of autovacuum workers if all is OK or nil otherwise.
+func autovacuumHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var countAutovacuumWorkers int64
+
+	row, err := conn.QueryRow(ctx, autovacuumQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
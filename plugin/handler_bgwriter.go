@@ -23,58 +23,24 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+// bgwriterQuery returns the bgwriter statistics query for a server version, since PostgreSQL 17 checkpoint
+// statistics are moved to pg_stat_checkpointer. Columns of pg_stat_bgwriter read by the base query are the same
+// since PostgreSQL 9.2, so it serves versions older than 10 too.
+func bgwriterQuery(version int) string {
+	return resolveQuery("bgwriter", version)
+}
+
 // bgwriterHandler executes select  with statistics from pg_stat_bgwriter
 // and returns JSON if all is OK or nil otherwise.
 func bgwriterHandler(ctx context.Context, conn PostgresClient,
 	_ string, _ map[string]string, _ ...string) (any, error) {
 	var bgwriterJSON string
 
-	const queryV1 = `
-		SELECT row_to_json(T)
-		FROM (
-			SELECT
-				checkpoints_timed,
-				checkpoints_req,
-				checkpoint_write_time,
-				checkpoint_sync_time,
-				buffers_checkpoint,
-				buffers_clean,
-				maxwritten_clean,
-				buffers_backend,
-				buffers_backend_fsync,
-				buffers_alloc
-			FROM pg_catalog.pg_stat_bgwriter
-		) T;
-	`
-
-	const queryV2 = `
-		SELECT row_to_json(T)
-		FROM (
-			SELECT  
-				psc.num_timed AS checkpoints_timed,
-				psc.num_requested AS checkpoints_req,
-				psc.write_time AS checkpoint_write_time,
-				psc.sync_time AS checkpoint_sync_time,
-				psc.buffers_written AS buffers_checkpoint,
-				psb.buffers_clean AS buffers_clean,
-				psb.maxwritten_clean AS maxwritten_clean,
-				psb.buffers_alloc AS buffers_alloc
-			FROM 
-				pg_catalog.pg_stat_checkpointer AS psc, 
-				pg_catalog.pg_stat_bgwriter AS psb
-		) T;
-	  `
-
-	var query string
-
-	version := conn.PostgresVersion()
-
-	switch {
-	// Postgres V17 and higher.
-	case version >= 170000:
-		query = queryV2
-	default:
-		query = queryV1
+	query := bgwriterQuery(conn.PostgresVersion())
+	if query == "" {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("bgwriter statistics are not supported by PostgreSQL %d", conn.PostgresVersion()),
+		)
 	}
 
 	row, err := conn.QueryRow(ctx, query)
@@ -91,5 +57,5 @@ func bgwriterHandler(ctx context.Context, conn PostgresClient,
 		return nil, errs.WrapConst(err, zbxerr.ErrorCannotFetchData) //nolint:wrapcheck
 	}
 
-	return bgwriterJSON, nil
+	return jsonResult(bgwriterJSON), nil
 }
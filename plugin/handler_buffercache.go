@@ -0,0 +1,107 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const buffercacheInstalledQuery = `SELECT EXISTS (
+	SELECT 1 FROM pg_catalog.pg_extension WHERE extname = 'pg_buffercache');`
+
+const buffercacheQuery = `WITH B AS (
+				SELECT relfilenode, reldatabase, isdirty FROM pg_buffercache
+			)
+			SELECT json_build_object(
+					'used', count(*) FILTER (WHERE relfilenode IS NOT NULL),
+					'dirty', count(*) FILTER (WHERE isdirty),
+					'unused', count(*) FILTER (WHERE relfilenode IS NULL),
+					'top_relations', (
+						SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.buffers DESC), '[]')
+						  FROM (
+							SELECT n.nspname AS schema, c.relname AS relation, count(*) AS buffers
+							  FROM B
+							  JOIN pg_catalog.pg_class c ON B.relfilenode = pg_catalog.pg_relation_filenode(c.oid)
+							  JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+							 WHERE B.reldatabase IN (0, (SELECT oid FROM pg_catalog.pg_database
+														  WHERE datname = current_database()))
+							 GROUP BY n.nspname, c.relname
+							 ORDER BY buffers DESC
+							 LIMIT $1
+						  ) T
+					)
+				)
+			  FROM B;`
+
+// buffercacheHandler gets shared buffers usage and relations using most buffers from the pg_buffercache extension
+// and returns JSON if all is OK or nil otherwise.
+func buffercacheHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var (
+		installed       bool
+		buffercacheJSON string
+	)
+
+	topN, err := strconv.Atoi(params["TopN"])
+	if err != nil {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("TopN must be an integer, %s", err.Error()),
+		)
+	}
+
+	if topN < 0 {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("TopN must not be negative"),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, buffercacheInstalledQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&installed)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	if !installed {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(
+			errors.New("the pg_buffercache extension is not installed in the database"),
+		)
+	}
+
+	row, err = conn.QueryRow(ctx, buffercacheQuery, topN)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&buffercacheJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(buffercacheJSON), nil
+}
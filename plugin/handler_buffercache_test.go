@@ -0,0 +1,122 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_buffercacheHandler(t *testing.T) {
+	type mock struct {
+		installed bool
+		row       *sqlmock.Rows
+		err       error
+	}
+
+	tests := []struct {
+		name    string
+		topN    string
+		mock    *mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			"5",
+			&mock{
+				installed: true,
+				row: sqlmock.NewRows([]string{"json"}).AddRow(
+					`{"used":10,"dirty":2,"unused":6,"top_relations":[{"schema":"public","relation":"t","buffers":4}]}`,
+				),
+			},
+			jsonResult(`{"used":10,"dirty":2,"unused":6,"top_relations":[{"schema":"public","relation":"t","buffers":4}]}`),
+			false,
+		},
+		{
+			"-notInstalled",
+			"5",
+			&mock{installed: false},
+			nil,
+			true,
+		},
+		{
+			"-queryErr",
+			"5",
+			&mock{
+				installed: true,
+				row:       sqlmock.NewRows([]string{"json"}),
+				err:       errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-invalidTopN",
+			"five",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-negativeTopN",
+			"-1",
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			if tt.mock != nil {
+				mock.ExpectQuery(`FROM pg_catalog.pg_extension`).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.mock.installed))
+
+				if tt.mock.installed {
+					mock.ExpectQuery(`FROM pg_buffercache`).
+						WithArgs(5).
+						WillReturnRows(tt.mock.row).
+						WillReturnError(tt.mock.err)
+				}
+			}
+
+			got, err := buffercacheHandler(
+				context.Background(), &PGConn{client: db}, keyBuffercache, map[string]string{"TopN": tt.topN},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buffercacheHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("buffercacheHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("buffercacheHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
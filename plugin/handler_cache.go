@@ -22,14 +22,14 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const cacheHitQuery = `SELECT round(sum(blks_hit)*100/sum(blks_hit+blks_read), 2) FROM pg_catalog.pg_stat_database;`
+
 // cacheHandler finds cache hit percent and returns int64 if all is OK or nil otherwise.
 func cacheHandler(ctx context.Context, conn PostgresClient,
 	_ string, _ map[string]string, _ ...string) (any, error) {
 	var cache float64
 
-	query := `SELECT round(sum(blks_hit)*100/sum(blks_hit+blks_read), 2) FROM pg_catalog.pg_stat_database;`
-
-	row, err := conn.QueryRow(ctx, query)
+	row, err := conn.QueryRow(ctx, cacheHitQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
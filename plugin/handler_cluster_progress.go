@@ -0,0 +1,77 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithClusterProgress is the first version with pg_stat_progress_cluster.
+const pgVersionWithClusterProgress = 120000
+
+// clusterProgressQuery returns progress of CLUSTER and VACUUM FULL commands, percent is calculated by scanned heap
+// blocks, so it is known in the seq scanning phase only. Relation names are resolved in the connected database only.
+const clusterProgressQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.pid), '[]')
+				FROM (
+					SELECT
+						p.pid,
+						p.datname,
+						CASE WHEN p.datname = current_database() THEN p.relid::regclass::text END AS relation,
+						p.command,
+						p.phase,
+						p.heap_blks_total,
+						p.heap_blks_scanned,
+						p.heap_tuples_written,
+						CASE
+							WHEN p.heap_blks_total > 0
+								THEN round(100.0 * p.heap_blks_scanned / p.heap_blks_total, 2)
+							ELSE 0
+						END AS percent
+					  FROM pg_catalog.pg_stat_progress_cluster p
+				) T;`
+
+// clusterProgressHandler gets progress of running CLUSTER and VACUUM FULL commands and returns JSON if all is OK or
+// nil otherwise.
+func clusterProgressHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var progressJSON string
+
+	if conn.PostgresVersion() < pgVersionWithClusterProgress {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("cluster progress requires PostgreSQL %d or newer", pgVersionWithClusterProgress),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, clusterProgressQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&progressJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(progressJSON), nil
+}
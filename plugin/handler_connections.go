@@ -22,12 +22,14 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// connectionsHandler executes select from pg_stat_activity command and returns JSON if all is OK or nil otherwise.
-func connectionsHandler(ctx context.Context, conn PostgresClient,
-	_ string, _ map[string]string, _ ...string) (any, error) {
-	var connectionsJSON string
+// connectionStates maps single-state connection keys to the pg_stat_activity state they count.
+var connectionStates = map[string]string{
+	keyConnectionsActive:            "active",
+	keyConnectionsIdle:              "idle",
+	keyConnectionsIdleInTransaction: "idle in transaction",
+}
 
-	query := `SELECT row_to_json(T)
+const connectionsQuery = `SELECT row_to_json(T)
 	FROM (
 		SELECT
 			sum(CASE WHEN state = 'active' THEN 1 ELSE 0 END) AS active,
@@ -42,7 +44,12 @@ func connectionsHandler(ctx context.Context, conn PostgresClient,
 			(SELECT count(*) FROM pg_prepared_xacts) AS prepared
 		FROM pg_stat_activity WHERE datid IS NOT NULL AND state IS NOT NULL) T;`
 
-	row, err := conn.QueryRow(ctx, query)
+// connectionsHandler executes select from pg_stat_activity command and returns JSON if all is OK or nil otherwise.
+func connectionsHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var connectionsJSON string
+
+	row, err := conn.QueryRow(ctx, connectionsQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
@@ -56,5 +63,72 @@ func connectionsHandler(ctx context.Context, conn PostgresClient,
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
-	return connectionsJSON, nil
+	return jsonResult(connectionsJSON), nil
+}
+
+// connectionsByUserQuery counts backends by user, background processes have no user and are counted under
+// the "background worker" name.
+const connectionsByUserQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.usename), '[]')
+	FROM (
+		SELECT
+			coalesce(usename, 'background worker') AS usename,
+			count(*) AS total,
+			sum(CASE WHEN state = 'active' THEN 1 ELSE 0 END) AS active,
+			sum(CASE WHEN state = 'idle' THEN 1 ELSE 0 END) AS idle
+		FROM pg_stat_activity
+		GROUP BY 1) T;`
+
+// connectionsByUserHandler counts backends by user and state and returns JSON if all is OK or nil otherwise.
+func connectionsByUserHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var connectionsJSON string
+
+	row, err := conn.QueryRow(ctx, connectionsByUserQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&connectionsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(connectionsJSON), nil
+}
+
+const connectionsStateQuery = `SELECT count(*)
+				FROM pg_stat_activity
+			   WHERE datid IS NOT NULL
+				 AND state = $1;`
+
+// connectionsStateHandler counts backends in the state related to a given key and returns int64 if all is OK
+// or nil otherwise.
+func connectionsStateHandler(ctx context.Context, conn PostgresClient,
+	key string, _ map[string]string, _ ...string) (any, error) {
+	var count int64
+
+	state, ok := connectionStates[key]
+	if !ok {
+		return nil, zbxerr.ErrorUnsupportedMetric
+	}
+
+	row, err := conn.QueryRow(ctx, connectionsStateQuery, state)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return count, nil
 }
@@ -0,0 +1,64 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// connectionsAvailableQuery returns the number of connection slots left for ordinary users: max_connections minus
+// the slots reserved for superusers and, since PostgreSQL 16, for roles with pg_use_reserved_connections, minus
+// client backends.
+const connectionsAvailableQuery = `SELECT S.max_connections - S.superuser_reserved - S.reserved - A.connections
+				FROM (
+					SELECT
+						max(setting::int) FILTER (WHERE name = 'max_connections') AS max_connections,
+						max(setting::int) FILTER (WHERE name = 'superuser_reserved_connections') AS superuser_reserved,
+						coalesce(max(setting::int) FILTER (WHERE name = 'reserved_connections'), 0) AS reserved
+					FROM pg_catalog.pg_settings
+					WHERE name IN ('max_connections', 'superuser_reserved_connections', 'reserved_connections')
+				) AS S,
+				(
+					SELECT count(*) AS connections
+					FROM pg_catalog.pg_stat_activity
+					WHERE backend_type = 'client backend'
+				) AS A;`
+
+// connectionsAvailableHandler gets the number of connection slots left for ordinary users if all is OK or nil
+// otherwise. It is negative if superusers use reserved slots.
+func connectionsAvailableHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var available int64
+
+	row, err := conn.QueryRow(ctx, connectionsAvailableQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&available)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return available, nil
+}
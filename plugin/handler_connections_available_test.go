@@ -0,0 +1,101 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_connectionsAvailableHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		mock    *mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			&mock{row: sqlmock.NewRows([]string{"available"}).AddRow(85)},
+			int64(85),
+			false,
+		},
+		{
+			"+reservedSlotsUsed",
+			&mock{row: sqlmock.NewRows([]string{"available"}).AddRow(-2)},
+			int64(-2),
+			false,
+		},
+		{
+			"-queryErr",
+			&mock{
+				row: sqlmock.NewRows([]string{"available"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			&mock{row: sqlmock.NewRows([]string{"available"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM pg_catalog.pg_settings`).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := connectionsAvailableHandler(
+				context.Background(),
+				&PGConn{client: db},
+				keyConnectionsAvailable,
+				nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("connectionsAvailableHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("connectionsAvailableHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"connectionsAvailableHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
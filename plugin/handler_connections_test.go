@@ -19,6 +19,7 @@ package plugin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 )
@@ -60,3 +61,94 @@ func TestPlugin_connectionsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugin_connectionsByUserHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := connectionsByUserHandler(context.Background(), sharedPool, keyConnectionsByUser, nil)
+	if err != nil {
+		t.Fatalf("Plugin.connectionsByUserHandler() error = %v", err)
+	}
+
+	var users []struct {
+		Usename string `json:"usename"`
+		Total   int64  `json:"total"`
+	}
+
+	err = json.Unmarshal([]byte(got.(jsonResult)), &users)
+	if err != nil {
+		t.Fatalf("Plugin.connectionsByUserHandler() returned invalid JSON %v: %s", got, err.Error())
+	}
+
+	for _, u := range users {
+		if u.Usename == "" || u.Total < 1 {
+			t.Errorf("Plugin.connectionsByUserHandler() returned invalid user %+v", u)
+		}
+	}
+}
+
+func TestPlugin_connectionsStateHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		ctx         context.Context
+		conn        *PGConn
+		key         string
+		params      map[string]string
+		extraParams []string
+	}
+	tests := []struct {
+		name    string
+		p       *Plugin
+		args    args
+		wantErr bool
+	}{
+		{
+			"connectionsStateHandler should return number of active connections if OK",
+			&Impl,
+			args{context.Background(), sharedPool, keyConnectionsActive, nil, []string{}},
+			false,
+		},
+		{
+			"connectionsStateHandler should return number of idle connections if OK",
+			&Impl,
+			args{context.Background(), sharedPool, keyConnectionsIdle, nil, []string{}},
+			false,
+		},
+		{
+			"connectionsStateHandler should return number of idle in transaction connections if OK",
+			&Impl,
+			args{context.Background(), sharedPool, keyConnectionsIdleInTransaction, nil, []string{}},
+			false,
+		},
+		{
+			"connectionsStateHandler should fail for unknown key",
+			&Impl,
+			args{context.Background(), sharedPool, keyConnections, nil, []string{}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := connectionsStateHandler(
+				tt.args.ctx, tt.args.conn, tt.args.key, tt.args.params, tt.args.extraParams...,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Plugin.connectionsStateHandler() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				if _, ok := got.(int64); !ok {
+					t.Errorf("Plugin.connectionsStateHandler() = %v, want int64", got)
+				}
+			}
+		})
+	}
+}
@@ -15,16 +15,64 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"golang.zabbix.com/sdk/errs"
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const (
+	// queryTimeoutDirective sets a timeout of a custom query in seconds in a leading comment, e.g.
+	// "-- zbx:timeout=60".
+	queryTimeoutDirective = "zbx:timeout="
+	maxQueryTimeout       = 600
+
+	// byteaTypeName is the database type name of binary columns.
+	byteaTypeName = "BYTEA"
+)
+
+// parseQueryTimeout returns a timeout set by the timeout directive in leading comment lines of a query,
+// ok is false if there is no directive.
+func parseQueryTimeout(query string) (time.Duration, bool, error) {
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		comment, ok := strings.CutPrefix(line, "--")
+		if !ok {
+			break
+		}
+
+		value, ok := strings.CutPrefix(strings.TrimSpace(comment), queryTimeoutDirective)
+		if !ok {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || seconds < 1 || seconds > maxQueryTimeout {
+			return 0, false, errs.Errorf(
+				"invalid query timeout %q, must be a number of seconds between 1 and %d", value, maxQueryTimeout,
+			)
+		}
+
+		return time.Duration(seconds) * time.Second, true, nil
+	}
+
+	return 0, false, nil
+}
+
 // customQueryHandler executes custom user queries from *.sql files.
 func customQueryHandler(ctx context.Context, conn PostgresClient,
 	_ string, params map[string]string, extraParams ...string) (any, error) {
@@ -41,14 +89,25 @@ func customQueryHandler(ctx context.Context, conn PostgresClient,
 	}
 	defer rows.Close()
 
-	// JSON marshaling
-	var data []string
-
 	columns, err := rows.Columns()
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
+	// A wide result, e.g. of SELECT * on a wide table, is refused before any row is read.
+	if maxColumns := limitParam(params, maxColumnsParam); maxColumns > 0 && len(columns) > maxColumns {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(
+			errs.Errorf("query %q returned %d columns, more than %d", queryName, len(columns), maxColumns),
+		)
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	binary := binaryColumns(columnTypes)
+
 	values := make([]any, len(columns))       //nolint:makezero
 	valuePointers := make([]any, len(values)) //nolint:makezero
 
@@ -57,8 +116,25 @@ func customQueryHandler(ctx context.Context, conn PostgresClient,
 	}
 
 	results := make(map[string]any)
+	maxRows := limitParam(params, maxRowsParam)
+
+	// JSON array is streamed into a single buffer, each row is encoded once.
+	var (
+		buf   bytes.Buffer
+		count int
+	)
+
+	enc := json.NewEncoder(&buf)
+
+	buf.WriteByte('[')
 
 	for rows.Next() {
+		if maxRows > 0 && count == maxRows {
+			return nil, zbxerr.ErrorCannotFetchData.Wrap(
+				errs.Errorf("query %q returned more than %d rows", queryName, maxRows),
+			)
+		}
+
 		err = rows.Scan(valuePointers...)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
@@ -68,14 +144,21 @@ func customQueryHandler(ctx context.Context, conn PostgresClient,
 			return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 		}
 
-		setResult(results, values, columns)
+		if count > 0 {
+			buf.WriteByte(',')
+		}
+
+		setResult(results, values, columns, binary)
 
-		jsonRes, err := json.Marshal(results)
+		err = enc.Encode(results)
 		if err != nil {
 			return nil, errs.Wrap(err, "cannot marshal results")
 		}
 
-		data = append(data, strings.TrimSpace(string(jsonRes)))
+		// Encode terminates each value with a newline.
+		buf.Truncate(buf.Len() - 1)
+
+		count++
 	}
 
 	// Any errors encountered by rows.Next or rows.Scan will be returned here
@@ -83,13 +166,105 @@ func customQueryHandler(ctx context.Context, conn PostgresClient,
 		return nil, errs.Wrap(err, "cannot fetch data")
 	}
 
-	return "[" + strings.Join(data, ",") + "]", nil
+	buf.WriteByte(']')
+
+	return jsonResult(buf.String()), nil
 }
 
-func setResult(results map[string]any, values []any, columns []string) {
+// customQueryMultiHandler executes custom user queries consisting of several statements from *.sql files
+// and returns JSON array with an array of rows per each result set.
+func customQueryMultiHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, extraParams ...string) (any, error) {
+	if len(extraParams) > 0 {
+		return nil, zbxerr.ErrorTooManyParameters.Wrap(
+			errors.New("query arguments are not supported for multi statement queries"),
+		)
+	}
+
+	results, err := conn.QueryMultiByName(
+		ctx, params["QueryName"], limitParam(params, maxRowsParam), limitParam(params, maxColumnsParam),
+	)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return collectResultSets(results)
+}
+
+// limitParam returns a limit of custom query results set in params from the configuration, 0 means no limit.
+func limitParam(params map[string]string, name string) int {
+	limit, err := strconv.Atoi(params[name])
+	if err != nil {
+		return 0
+	}
+
+	return limit
+}
+
+// collectResultSets converts text results of the simple protocol to an array of arrays of rows.
+// Results of statements that don't return rows (e.g. SET) are skipped.
+func collectResultSets(results []*pgconn.Result) ([][]map[string]any, error) {
+	sets := make([][]map[string]any, 0, len(results))
+
+	for _, res := range results {
+		if res.Err != nil {
+			return nil, zbxerr.ErrorCannotFetchData.Wrap(res.Err)
+		}
+
+		if len(res.FieldDescriptions) == 0 {
+			continue
+		}
+
+		rows := make([]map[string]any, 0, len(res.Rows))
+
+		for _, values := range res.Rows {
+			row := make(map[string]any, len(values))
+
+			for i, v := range values {
+				name := string(res.FieldDescriptions[i].Name)
+				if v == nil {
+					row[name] = nil
+
+					continue
+				}
+
+				row[name] = string(v)
+			}
+
+			rows = append(rows, row)
+		}
+
+		sets = append(sets, rows)
+	}
+
+	return sets, nil
+}
+
+// binaryColumns tells which columns hold binary (bytea) data, the other byte slices are text.
+func binaryColumns(columnTypes []*sql.ColumnType) []bool {
+	binary := make([]bool, len(columnTypes))
+
+	for i, ct := range columnTypes {
+		binary[i] = strings.EqualFold(ct.DatabaseTypeName(), byteaTypeName)
+	}
+
+	return binary
+}
+
+// setResult sets values of a row to results by column names. Values of binary columns are base64 encoded, so
+// they survive JSON encoding, byte slices of other columns are text. The text is in ClientEncoding of the session
+// and JSON encoding replaces bytes which are not valid UTF-8 with U+FFFD, so with ClientEncoding other than UTF8
+// only ASCII text survives.
+func setResult(results map[string]any, values []any, columns []string, binary []bool) {
 	for i, value := range values {
 		switch v := value.(type) {
 		case []uint8:
+			if binary[i] {
+				results[columns[i]] = base64.StdEncoding.EncodeToString(v)
+
+				continue
+			}
+
 			results[columns[i]] = string(v)
 		default:
 			results[columns[i]] = value
@@ -0,0 +1,94 @@
+//go:build postgresql_tests
+// +build postgresql_tests
+
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/omeid/go-yarn"
+)
+
+func TestPGConn_QueryMultiByName_rollsBack(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage := yarn.NewFromMap(map[string]string{
+		"set.sql":    "SET application_name = 'multi_set'; SELECT current_setting('application_name') AS name;",
+		"commit.sql": "COMMIT; SELECT 1 AS one;",
+		"rows.sql":   "SELECT 1 AS one; SELECT generate_series(1, 5) AS n;",
+		"wide.sql":   "SELECT 1 AS one; SELECT 1 AS a, 2 AS b, 3 AS c;",
+	})
+
+	conn := &PGConn{client: sharedPool.client, version: sharedPool.version, queryStorage: &storage}
+
+	// A single connection makes the next queries run on the connection the multi query used.
+	conn.client.SetMaxOpenConns(1)
+	defer conn.client.SetMaxOpenConns(0)
+
+	results, err := conn.QueryMultiByName(context.Background(), "set", 0, 0)
+	if err != nil {
+		t.Fatalf("PGConn.QueryMultiByName() error = %v", err)
+	}
+
+	sets, err := collectResultSets(results)
+	if err != nil {
+		t.Fatalf("collectResultSets() error = %v", err)
+	}
+
+	if len(sets) != 1 || sets[0][0]["name"] != "multi_set" {
+		t.Fatalf("PGConn.QueryMultiByName() = %v, want application_name set within the query", sets)
+	}
+
+	var name string
+
+	err = conn.client.QueryRow("SELECT current_setting('application_name');").Scan(&name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name == "multi_set" {
+		t.Fatalf("application_name = %q after the query, want the setting rolled back", name)
+	}
+
+	_, err = conn.QueryMultiByName(context.Background(), "commit", 0, 0)
+	if err == nil {
+		t.Fatal("PGConn.QueryMultiByName() error = nil, want error for a query ending the transaction")
+	}
+
+	_, err = conn.QueryMultiByName(context.Background(), "rows", 3, 0)
+	if err == nil {
+		t.Fatal("PGConn.QueryMultiByName() error = nil, want error for more rows than the limit")
+	}
+
+	results, err = conn.QueryMultiByName(context.Background(), "rows", 6, 0)
+	if err != nil {
+		t.Fatalf("PGConn.QueryMultiByName() error = %v, want the connection usable after the limit error", err)
+	}
+
+	if sets, _ := collectResultSets(results); len(sets) != 2 || len(sets[1]) != 5 {
+		t.Fatalf("PGConn.QueryMultiByName() = %v, want 2 result sets with 1 and 5 rows", sets)
+	}
+
+	_, err = conn.QueryMultiByName(context.Background(), "wide", 0, 2)
+	if err == nil {
+		t.Fatal("PGConn.QueryMultiByName() error = nil, want error for more columns than the limit")
+	}
+}
@@ -0,0 +1,523 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/omeid/go-yarn"
+)
+
+func Test_collectResultSets(t *testing.T) {
+	t.Parallel()
+
+	fields := func(names ...string) []pgproto3.FieldDescription {
+		res := make([]pgproto3.FieldDescription, 0, len(names))
+		for _, n := range names {
+			res = append(res, pgproto3.FieldDescription{Name: []byte(n)})
+		}
+
+		return res
+	}
+
+	tests := []struct {
+		name    string
+		results []*pgconn.Result
+		want    string
+		wantErr bool
+	}{
+		{
+			"+severalResultSets",
+			[]*pgconn.Result{
+				{
+					FieldDescriptions: fields("a", "b"),
+					Rows:              [][][]byte{{[]byte("1"), []byte("foo")}, {[]byte("2"), nil}},
+				},
+				{
+					FieldDescriptions: fields("c"),
+					Rows:              [][][]byte{{[]byte("bar")}},
+				},
+			},
+			`[[{"a":"1","b":"foo"},{"a":"2","b":null}],[{"c":"bar"}]]`,
+			false,
+		},
+		{
+			"+skipsCommandsWithoutRows",
+			[]*pgconn.Result{
+				{CommandTag: pgconn.CommandTag("SET")},
+				{FieldDescriptions: fields("a"), Rows: [][][]byte{{[]byte("1")}}},
+			},
+			`[[{"a":"1"}]]`,
+			false,
+		},
+		{
+			"+emptyResultSet",
+			[]*pgconn.Result{{FieldDescriptions: fields("a")}},
+			`[[]]`,
+			false,
+		},
+		{
+			"+noResults",
+			nil,
+			`[]`,
+			false,
+		},
+		{
+			"-resultErr",
+			[]*pgconn.Result{{Err: errors.New("fail")}},
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sets, err := collectResultSets(tt.results)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("collectResultSets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			got, err := formatResult(keyCustomQueryMulti, sets, false)
+			if err != nil {
+				t.Fatalf("formatResult() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("collectResultSets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_customQueryMultiHandler_args(t *testing.T) {
+	t.Parallel()
+
+	_, err := customQueryMultiHandler(
+		context.Background(), &PGConn{}, keyCustomQueryMulti, map[string]string{"QueryName": "foo"}, "bar",
+	)
+	if err == nil {
+		t.Fatal("customQueryMultiHandler() error = nil, want error for query arguments")
+	}
+}
+
+func Test_customQueryHandler_maxRows(t *testing.T) {
+	t.Parallel()
+
+	rows := func(n int) *sqlmock.Rows {
+		r := sqlmock.NewRows([]string{"id"})
+		for i := 0; i < n; i++ {
+			r.AddRow(i)
+		}
+
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		maxRows int
+		rows    *sqlmock.Rows
+		want    any
+		wantErr bool
+	}{
+		{"+belowCap", 3, rows(2), jsonResult(`[{"id":0},{"id":1}]`), false},
+		{"+atCap", 2, rows(2), jsonResult(`[{"id":0},{"id":1}]`), false},
+		{"+noCap", 0, rows(2), jsonResult(`[{"id":0},{"id":1}]`), false},
+		{"-overCap", 2, rows(3), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`SELECT id FROM t`).WillReturnRows(tt.rows)
+
+			storage := yarn.NewFromMap(map[string]string{"ids.sql": "SELECT id FROM t;"})
+			conn := &PGConn{client: db, queryStorage: &storage}
+
+			got, err := customQueryHandler(
+				context.Background(),
+				conn,
+				keyCustomQuery,
+				map[string]string{"QueryName": "ids", maxRowsParam: strconv.Itoa(tt.maxRows)},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("customQueryHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("customQueryHandler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_customQueryHandler_maxColumns(t *testing.T) {
+	t.Parallel()
+
+	wideRows := func(n int) *sqlmock.Rows {
+		columns := make([]string, n)
+		values := make([]driver.Value, n)
+
+		for i := range columns {
+			columns[i] = fmt.Sprintf("c%d", i)
+			values[i] = i
+		}
+
+		return sqlmock.NewRows(columns).AddRow(values...)
+	}
+
+	tests := []struct {
+		name       string
+		maxColumns int
+		columns    int
+		wantErr    bool
+	}{
+		{"+belowCap", 100, 99, false},
+		{"+atCap", 100, 100, false},
+		{"+noCap", 0, 500, false},
+		{"-overCap", 100, 101, true},
+		{"-wideTable", 100, 1000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`SELECT \* FROM t`).WillReturnRows(wideRows(tt.columns))
+
+			storage := yarn.NewFromMap(map[string]string{"wide.sql": "SELECT * FROM t;"})
+			conn := &PGConn{client: db, queryStorage: &storage}
+
+			got, err := customQueryHandler(
+				context.Background(),
+				conn,
+				keyCustomQuery,
+				map[string]string{"QueryName": "wide", maxColumnsParam: strconv.Itoa(tt.maxColumns)},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("customQueryHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), "columns") {
+					t.Fatalf("customQueryHandler() error = %v, want the column limit error", err)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("customQueryHandler() = nil, want rows")
+			}
+		})
+	}
+}
+
+func Test_customQueryHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		rows *sqlmock.Rows
+		want any
+	}{
+		{
+			"+types",
+			sqlmock.NewRows([]string{"b", "a", "c"}).
+				AddRow([]byte("text"), int64(1), nil).
+				AddRow("<tag> & \"quoted\"", 2.5, true),
+			jsonResult(`[{"a":1,"b":"text","c":null},{"a":2.5,"b":"\u003ctag\u003e \u0026 \"quoted\"","c":true}]`),
+		},
+		{
+			"+bytea",
+			sqlmock.NewRowsWithColumnDefinition(
+				sqlmock.NewColumn("data").OfType("BYTEA", []byte{}),
+				sqlmock.NewColumn("name").OfType("TEXT", []byte{}),
+			).
+				AddRow([]byte{0x00, 0xff, 0x7f, '"'}, []byte("text")).
+				AddRow(nil, []byte("null")),
+			jsonResult(`[{"data":"AP9/Ig==","name":"text"},{"data":null,"name":"null"}]`),
+		},
+		{
+			"+singleRow",
+			sqlmock.NewRows([]string{"a"}).AddRow("foo"),
+			jsonResult(`[{"a":"foo"}]`),
+		},
+		{
+			"+noRows",
+			sqlmock.NewRows([]string{"a"}),
+			jsonResult(`[]`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`SELECT \* FROM t`).WillReturnRows(tt.rows)
+
+			storage := yarn.NewFromMap(map[string]string{"all.sql": "SELECT * FROM t;"})
+			conn := &PGConn{client: db, queryStorage: &storage}
+
+			got, err := customQueryHandler(
+				context.Background(), conn, keyCustomQuery, map[string]string{"QueryName": "all"},
+			)
+			if err != nil {
+				t.Fatalf("customQueryHandler() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("customQueryHandler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// staticRowsDriver is a database driver answering any query with the same prebuilt rows, so reading them doesn't
+// allocate and a benchmark measures only the handler.
+type staticRowsDriver struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+type staticRowsConn struct{ d *staticRowsDriver }
+
+type staticRows struct {
+	d *staticRowsDriver
+	i int
+}
+
+func (d *staticRowsDriver) Open(string) (driver.Conn, error) {
+	return &staticRowsConn{d}, nil
+}
+
+func (d *staticRowsDriver) Connect(context.Context) (driver.Conn, error) {
+	return &staticRowsConn{d}, nil
+}
+
+func (d *staticRowsDriver) Driver() driver.Driver { return d }
+
+func (c *staticRowsConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *staticRowsConn) Close() error { return nil }
+
+func (c *staticRowsConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *staticRowsConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &staticRows{d: c.d}, nil
+}
+
+func (r *staticRows) Columns() []string { return r.d.columns }
+func (r *staticRows) Close() error      { return nil }
+
+func (r *staticRows) Next(dest []driver.Value) error {
+	if r.i == len(r.d.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.d.rows[r.i])
+	r.i++
+
+	return nil
+}
+
+// customQueryPerRowMarshal is the former customQueryHandler, marshaling each row to a separate string and joining
+// them, kept as the baseline of Benchmark_customQueryHandler.
+func customQueryPerRowMarshal(ctx context.Context, conn PostgresClient, queryName string) (any, error) {
+	rows, err := conn.QueryByName(ctx, queryName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var data []string
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]any, len(columns))
+	valuePointers := make([]any, len(values))
+
+	for i := range values {
+		valuePointers[i] = &values[i]
+	}
+
+	results := make(map[string]any)
+	binary := make([]bool, len(columns))
+
+	for rows.Next() {
+		err = rows.Scan(valuePointers...)
+		if err != nil {
+			return nil, err
+		}
+
+		setResult(results, values, columns, binary)
+
+		jsonRes, err := json.Marshal(results)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, strings.TrimSpace(string(jsonRes)))
+	}
+
+	return "[" + strings.Join(data, ",") + "]", rows.Err()
+}
+
+// Benchmark_customQueryHandler compares the streamed encoding of a 100k rows result with the former per row
+// marshaling.
+func Benchmark_customQueryHandler(b *testing.B) {
+	const rowCount = 100000
+
+	d := &staticRowsDriver{columns: []string{"id", "name"}, rows: make([][]driver.Value, rowCount)}
+	for j := range d.rows {
+		d.rows[j] = []driver.Value{int64(j), "name"}
+	}
+
+	db := sql.OpenDB(d)
+	defer db.Close()
+
+	storage := yarn.NewFromMap(map[string]string{"rows.sql": "SELECT id, name FROM t;"})
+	conn := &PGConn{client: db, queryStorage: &storage}
+
+	b.Run("perRowMarshal", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_, err := customQueryPerRowMarshal(context.Background(), conn, "rows")
+			if err != nil {
+				b.Fatalf("customQueryPerRowMarshal() error = %v", err)
+			}
+		}
+	})
+
+	b.Run("streamed", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_, err := customQueryHandler(context.Background(), conn, keyCustomQuery, map[string]string{"QueryName": "rows"})
+			if err != nil {
+				b.Fatalf("customQueryHandler() error = %v", err)
+			}
+		}
+	})
+}
+
+func Test_parseQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    time.Duration
+		wantOk  bool
+		wantErr bool
+	}{
+		{"+directive", "-- zbx:timeout=60\nSELECT 1;", time.Minute, true, false},
+		{"+noSpace", "--zbx:timeout=5\nSELECT 1;", 5 * time.Second, true, false},
+		{"+afterComments", "\n-- heavy report\n  --  zbx:timeout= 600 \nSELECT 1;", 600 * time.Second, true, false},
+		{"+none", "-- heavy report\nSELECT 1;", 0, false, false},
+		{"+afterQuery", "SELECT 1;\n-- zbx:timeout=60", 0, false, false},
+		{"+empty", "", 0, false, false},
+		{"-notNumber", "-- zbx:timeout=1m\nSELECT 1;", 0, false, true},
+		{"-zero", "-- zbx:timeout=0\nSELECT 1;", 0, false, true},
+		{"-tooLong", "-- zbx:timeout=601\nSELECT 1;", 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok, err := parseQueryTimeout(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQueryTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want || ok != tt.wantOk {
+				t.Fatalf("parseQueryTimeout() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestPGConn_queryTimeoutByName(t *testing.T) {
+	t.Parallel()
+
+	storage := yarn.NewFromMap(map[string]string{
+		"report.sql":  "-- zbx:timeout=120\nSELECT 1;",
+		"plain.sql":   "SELECT 1;",
+		"invalid.sql": "-- zbx:timeout=abc\nSELECT 1;",
+	})
+	conn := &PGConn{queryStorage: &storage}
+
+	got, ok, err := conn.queryTimeoutByName("report")
+	if err != nil || !ok || got != 2*time.Minute {
+		t.Fatalf("PGConn.queryTimeoutByName(report) = %v, %v, %v, want 2m, true, nil", got, ok, err)
+	}
+
+	for _, name := range []string{"plain", "missing"} {
+		_, ok, err = conn.queryTimeoutByName(name)
+		if err != nil || ok {
+			t.Fatalf("PGConn.queryTimeoutByName(%s) = %v, %v, want false, nil", name, ok, err)
+		}
+	}
+
+	_, _, err = conn.queryTimeoutByName("invalid")
+	if err == nil {
+		t.Fatal("PGConn.queryTimeoutByName(invalid) error = nil, want error")
+	}
+}
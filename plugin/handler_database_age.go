@@ -22,16 +22,17 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const databaseAgeQuery = `SELECT age(datfrozenxid)
+		FROM pg_catalog.pg_database
+   		WHERE datistemplate = false
+			 AND datname = $1;`
+
 // databaseAgeHandler gets age of specific database respectively or nil otherwise.
 func databaseAgeHandler(ctx context.Context, conn PostgresClient,
 	_ string, params map[string]string, _ ...string) (any, error) {
 	var countAge int64
 
-	query := `SELECT age(datfrozenxid)
-		FROM pg_catalog.pg_database
-   		WHERE datistemplate = false
-			 AND datname = $1;`
-	row, err := conn.QueryRow(ctx, query, params["Database"])
+	row, err := conn.QueryRow(ctx, databaseAgeQuery, params["Database"])
 
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
@@ -48,3 +49,31 @@ func databaseAgeHandler(ctx context.Context, conn PostgresClient,
 
 	return countAge, nil
 }
+
+const allDatabasesAgeQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.datname), '[]')
+				FROM (
+					SELECT datname, age(datfrozenxid) AS age
+					  FROM pg_catalog.pg_database
+				) T;`
+
+// allDatabasesAgeHandler gets age of the frozen xid of all databases and returns JSON if all is OK or nil otherwise.
+func allDatabasesAgeHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var ageJSON string
+
+	row, err := conn.QueryRow(ctx, allDatabasesAgeQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&ageJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(ageJSON), nil
+}
@@ -20,6 +20,7 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -59,3 +60,19 @@ func TestPlugin_databasesAgeHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugin_allDatabasesAgeHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := allDatabasesAgeHandler(context.Background(), sharedPool, keyDatabaseAgeAll, nil)
+	if err != nil {
+		t.Fatalf("Plugin.allDatabasesAgeHandler() error = %v", err)
+	}
+
+	if !strings.Contains(string(got.(jsonResult)), `"datname":"postgres"`) {
+		t.Errorf("Plugin.allDatabasesAgeHandler() = %v, want age of the postgres database", got)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// databaseObjectsQuery counts relations of the connected database by kind, system catalogs and TOAST relations
+// are not counted.
+const databaseObjectsQuery = `SELECT json_build_object(
+					'tables', count(*) FILTER (WHERE c.relkind = 'r'),
+					'indexes', count(*) FILTER (WHERE c.relkind = 'i'),
+					'views', count(*) FILTER (WHERE c.relkind = 'v'),
+					'materialized_views', count(*) FILTER (WHERE c.relkind = 'm'),
+					'sequences', count(*) FILTER (WHERE c.relkind = 'S'),
+					'partitioned_tables', count(*) FILTER (WHERE c.relkind = 'p')
+				)
+				  FROM pg_catalog.pg_class c
+				  JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+				 WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+				   AND n.nspname !~ '^pg_toast';`
+
+// databaseObjectsHandler gets numbers of tables, indexes, views, materialized views, sequences and partitioned
+// tables of the connected database and returns JSON if all is OK or nil otherwise.
+func databaseObjectsHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var objectsJSON string
+
+	row, err := conn.QueryRow(ctx, databaseObjectsQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&objectsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(objectsJSON), nil
+}
@@ -0,0 +1,106 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_databaseObjectsHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		mock    mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`{"tables" : 120, "indexes" : 310, "views" : 4, "materialized_views" : 1, "sequences" : 95, ` +
+					`"partitioned_tables" : 2}`,
+			)},
+			jsonResult(`{"tables" : 120, "indexes" : 310, "views" : 4, "materialized_views" : 1, "sequences" : 95, ` +
+				`"partitioned_tables" : 2}`),
+			false,
+		},
+		{
+			"+emptyDatabase",
+			mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`{"tables" : 0, "indexes" : 0, "views" : 0, "materialized_views" : 0, "sequences" : 0, ` +
+					`"partitioned_tables" : 0}`,
+			)},
+			jsonResult(`{"tables" : 0, "indexes" : 0, "views" : 0, "materialized_views" : 0, "sequences" : 0, ` +
+				`"partitioned_tables" : 0}`),
+			false,
+		},
+		{
+			"-queryErr",
+			mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			mock{row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`JOIN pg_catalog.pg_namespace`).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := databaseObjectsHandler(
+				context.Background(), &PGConn{client: db}, keyDatabaseObjects, nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("databaseObjectsHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("databaseObjectsHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"databaseObjectsHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
@@ -22,17 +22,17 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const databaseSizeQuery = `SELECT pg_database_size(datname::text)
+		FROM pg_catalog.pg_database
+   		WHERE datistemplate = false
+			 AND datname = $1;`
+
 // databaseSizeHandler gets info about count and size of archive files and returns JSON if all is OK or nil otherwise.
 func databaseSizeHandler(ctx context.Context, conn PostgresClient,
 	_ string, params map[string]string, _ ...string) (any, error) {
 	var countSize int64
 
-	query := `SELECT pg_database_size(datname::text)
-		FROM pg_catalog.pg_database
-   		WHERE datistemplate = false
-			 AND datname = $1;`
-
-	row, err := conn.QueryRow(ctx, query, params["Database"])
+	row, err := conn.QueryRow(ctx, databaseSizeQuery, params["Database"])
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
@@ -20,7 +20,11 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"net"
 	"testing"
+	"time"
+
+	"golang.zabbix.com/sdk/tlsconfig"
 )
 
 func TestPlugin_databasesSizeHandler(t *testing.T) {
@@ -60,3 +64,36 @@ func TestPlugin_databasesSizeHandler(t *testing.T) {
 		})
 	}
 }
+
+// A parametrized query must work over the simple protocol too, pgx interpolates $1 on the client side.
+func TestPlugin_databasesSizeHandler_simpleProtocol(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pgAddr, pgUser, pgPwd, pgDb := getEnv()
+
+	host, port, err := net.SplitHostPort(pgAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dsn := createDNS(host, port, pgDb, pgUser, pgPwd, cacheModeSimple, "", "", "", tlsconfig.Details{}, nil)
+
+	client, err := createClient(dsn, 5*time.Second, nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	conn := &PGConn{client: client, version: sharedPool.version}
+
+	_, err = databaseSizeHandler(
+		context.Background(), conn, keyDatabaseSize, map[string]string{"Database": pgDb},
+	)
+	if err != nil {
+		t.Errorf("Plugin.databaseSizeHandler() over simple protocol error = %v", err)
+	}
+}
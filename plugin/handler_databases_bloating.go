@@ -22,17 +22,22 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// databasesBloatingHandler gets info about count and size of archive files and returns JSON if all is OK or nil otherwise.
+// bloatingTablesCondition selects tables with more than 20% of dead tuples out of more than 50 tuples.
+const bloatingTablesCondition = `(n_dead_tup/(n_live_tup+n_dead_tup)::float8) > 0.2
+		 		 AND (n_live_tup+n_dead_tup) > 50`
+
+const databasesBloatingQuery = `SELECT count(*)
+				FROM pg_catalog.pg_stat_all_tables
+	   		   WHERE ` + bloatingTablesCondition + `;`
+
+// databasesBloatingHandler gets the number of bloating tables and returns int64 if all is OK or nil otherwise.
+// pg_stat_all_tables only has tables of the database the connection is made to, so the number is scoped
+// to the connected database, not to the whole cluster.
 func databasesBloatingHandler(ctx context.Context, conn PostgresClient,
 	_ string, _ map[string]string, _ ...string) (any, error) {
 	var countBloating int64
 
-	query := `SELECT count(*)
-				FROM pg_catalog.pg_stat_all_tables
-	   		   WHERE (n_dead_tup/(n_live_tup+n_dead_tup)::float8) > 0.2
-		 		 AND (n_live_tup+n_dead_tup) > 50;`
-
-	row, err := conn.QueryRow(ctx, query)
+	row, err := conn.QueryRow(ctx, databasesBloatingQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
@@ -48,3 +53,64 @@ func databasesBloatingHandler(ctx context.Context, conn PostgresClient,
 
 	return countBloating, nil
 }
+
+const databaseBloatingByDBQuery = `SELECT row_to_json(T)
+				FROM (
+					SELECT current_database() AS datname, count(*) AS bloating_tables
+					  FROM pg_catalog.pg_stat_all_tables
+					 WHERE ` + bloatingTablesCondition + `
+				) T;`
+
+// databaseBloatingByDBHandler gets the number of bloating tables in the connected database and returns JSON
+// with the database name if all is OK or nil otherwise.
+func databaseBloatingByDBHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var bloatingJSON string
+
+	row, err := conn.QueryRow(ctx, databaseBloatingByDBQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&bloatingJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(bloatingJSON), nil
+}
+
+const databaseBloatingDiscoveryQuery = `SELECT json_build_object('data', coalesce(json_agg(json_build_object(
+					'{#DBNAME}', current_database(),
+					'{#SCHEMA}', schemaname,
+					'{#TABLE}', relname
+				) ORDER BY schemaname, relname), '[]'))
+				FROM pg_catalog.pg_stat_all_tables
+			   WHERE ` + bloatingTablesCondition + `;`
+
+// databaseBloatingDiscoveryHandler gets bloating tables of the connected database and returns
+// JSON discovery rule if all is OK or nil otherwise.
+func databaseBloatingDiscoveryHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var discoveryJSON string
+
+	row, err := conn.QueryRow(ctx, databaseBloatingDiscoveryQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&discoveryJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(discoveryJSON), nil
+}
@@ -19,6 +19,7 @@ package plugin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 )
@@ -60,3 +61,52 @@ func TestPlugin_databasesBloatingHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugin_databaseBloatingByDBHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := databaseBloatingByDBHandler(context.Background(), sharedPool, keyDatabaseBloatingByDB, nil)
+	if err != nil {
+		t.Fatalf("Plugin.databaseBloatingByDBHandler() error = %v", err)
+	}
+
+	var res struct {
+		Datname        string `json:"datname"`
+		BloatingTables *int64 `json:"bloating_tables"`
+	}
+
+	err = json.Unmarshal([]byte(got.(jsonResult)), &res)
+	if err != nil {
+		t.Fatalf("Plugin.databaseBloatingByDBHandler() returned invalid JSON: %s", err.Error())
+	}
+
+	if res.Datname == "" || res.BloatingTables == nil {
+		t.Fatalf("Plugin.databaseBloatingByDBHandler() = %s, want datname and bloating_tables", got)
+	}
+}
+
+func TestPlugin_databaseBloatingDiscoveryHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := databaseBloatingDiscoveryHandler(
+		context.Background(), sharedPool, keyDatabaseBloatingDiscovery, nil,
+	)
+	if err != nil {
+		t.Fatalf("Plugin.databaseBloatingDiscoveryHandler() error = %v", err)
+	}
+
+	var res struct {
+		Data []map[string]string `json:"data"`
+	}
+
+	err = json.Unmarshal([]byte(got.(jsonResult)), &res)
+	if err != nil || res.Data == nil {
+		t.Fatalf("Plugin.databaseBloatingDiscoveryHandler() = %s, want discovery JSON, error = %v", got, err)
+	}
+}
@@ -22,16 +22,40 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// databasesDiscoveryHandler gets names of all databases and returns JSON if all is OK or nil otherwise.
-func databasesDiscoveryHandler(ctx context.Context, conn PostgresClient,
-	_ string, _ map[string]string, _ ...string) (any, error) {
-	var databasesJSON string
-
-	query := `SELECT json_build_object ('data',json_agg(json_build_object('{#DBNAME}',d.datname)))
+const databasesDiscoveryQuery = `SELECT json_build_object ('data',json_agg(json_build_object('{#DBNAME}',d.datname)))
 				FROM pg_database d
 			   WHERE NOT datistemplate
 				 AND datallowconn;`
 
+// databasesDiscoveryExtQuery discovers databases including templates with their size and connection limits.
+// {#CONNLIMIT} is the effective limit, max_connections if the database has no limit of its own, and {#DBSIZE} is
+// empty for databases the user can't connect to, as pg_database_size requires the CONNECT privilege.
+const databasesDiscoveryExtQuery = `SELECT json_build_object('data', coalesce(json_agg(json_build_object(
+					'{#DBNAME}', d.datname,
+					'{#DBSIZE}', coalesce(CASE
+						WHEN has_database_privilege(d.oid, 'CONNECT') THEN pg_database_size(d.oid)
+					END::text, ''),
+					'{#CONNLIMIT}', CASE
+						WHEN d.datconnlimit < 0 THEN current_setting('max_connections')::int
+						ELSE least(d.datconnlimit, current_setting('max_connections')::int)
+					END::text,
+					'{#DATCONNLIMIT}', d.datconnlimit::text,
+					'{#ISTEMPLATE}', d.datistemplate::int::text
+				) ORDER BY d.datname), '[]'))
+				FROM pg_catalog.pg_database d
+			   WHERE datallowconn;`
+
+// databasesDiscoveryHandler gets names of all databases, with their size and connection limits for
+// pgsql.db.discovery.ext, and returns JSON if all is OK or nil otherwise.
+func databasesDiscoveryHandler(ctx context.Context, conn PostgresClient,
+	key string, _ map[string]string, _ ...string) (any, error) {
+	var databasesJSON string
+
+	query := databasesDiscoveryQuery
+	if key == keyDatabasesDiscoveryExt {
+		query = databasesDiscoveryExtQuery
+	}
+
 	row, err := conn.QueryRow(ctx, query)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
@@ -46,5 +70,5 @@ func databasesDiscoveryHandler(ctx context.Context, conn PostgresClient,
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
-	return databasesJSON, nil
+	return jsonResult(databasesJSON), nil
 }
@@ -48,6 +48,12 @@ func TestPlugin_databasesDiscoveryHandler(t *testing.T) {
 			args{context.Background(), sharedPool, keyDatabasesDiscovery, nil, []string{}},
 			false,
 		},
+		{
+			fmt.Sprintf("databasesDiscoveryHandler should return JSON with sizes and limits if OK "),
+			&Impl,
+			args{context.Background(), sharedPool, keyDatabasesDiscoveryExt, nil, []string{}},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,7 +64,7 @@ func TestPlugin_databasesDiscoveryHandler(t *testing.T) {
 				return
 			}
 
-			if len(got.(string)) == 0 {
+			if len(got.(jsonResult)) == 0 {
 				t.Errorf("Plugin.databaseDiscoveryHandler() = %v", got)
 			}
 		})
@@ -54,6 +54,12 @@ func TestPlugin_dbStatHandler(t *testing.T) {
 			args{context.Background(), sharedPool, keyDBStat, nil, []string{}},
 			false,
 		},
+		{
+			fmt.Sprintf("dbStatHandler should return json with data for pgsql.dbstat.by_db key if OK"),
+			&Impl,
+			args{context.Background(), sharedPool, keyDBStatByDB, nil, []string{}},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,62 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// extensionsQuery returns installed and available extensions of the connected database by extension name,
+// installed_version and schema are null for extensions which are available but not installed.
+const extensionsQuery = `SELECT coalesce(json_object_agg(T.name, row_to_json(T)), '{}')
+				FROM (
+					SELECT
+						coalesce(e.extname, a.name) AS name,
+						n.nspname AS schema,
+						e.extversion AS installed_version,
+						a.default_version,
+						e.extversion IS NOT NULL AND a.default_version IS NOT NULL
+							AND e.extversion <> a.default_version AS update_available
+					  FROM pg_catalog.pg_extension e
+					  LEFT JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+					  FULL JOIN pg_catalog.pg_available_extensions a ON a.name = e.extname
+				) T;`
+
+// extensionsHandler gets installed and available extensions with their versions and returns JSON if all is OK
+// or nil otherwise.
+func extensionsHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var extensionsJSON string
+
+	row, err := conn.QueryRow(ctx, extensionsQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&extensionsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(extensionsJSON), nil
+}
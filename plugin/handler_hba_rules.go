@@ -0,0 +1,78 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithHBAFileRules is the first version with pg_hba_file_rules.
+const pgVersionWithHBAFileRules = 100000
+
+// hbaRulesQuery returns rules of pg_hba.conf in the order of the file, error is set for a rule which can't be
+// applied.
+const hbaRulesQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.line_number), '[]')
+				FROM (
+					SELECT
+						line_number,
+						type,
+						database,
+						user_name,
+						address,
+						auth_method,
+						error
+					  FROM pg_catalog.pg_hba_file_rules
+				) T;`
+
+// hbaRulesHandler gets client authentication rules of pg_hba.conf and returns JSON if all is OK or nil otherwise.
+func hbaRulesHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var rulesJSON string
+
+	if conn.PostgresVersion() < pgVersionWithHBAFileRules {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("pg_hba_file_rules requires PostgreSQL %d or newer", pgVersionWithHBAFileRules),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, hbaRulesQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&rulesJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == sqlStateInsufficientPrivilege {
+			return nil, zbxerr.ErrorCannotFetchData.Wrap(
+				errs.Wrap(err, "pg_hba_file_rules requires superuser or pg_read_all_settings role"),
+			)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(rulesJSON), nil
+}
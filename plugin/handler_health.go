@@ -0,0 +1,163 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"strings"
+
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// Overall statuses of pgsql.health.
+const (
+	healthStatusOK     = "ok"
+	healthStatusFailed = "failed"
+)
+
+// healthConnectionsQuery returns client connections in percent of max_connections.
+const healthConnectionsQuery = `SELECT round(100.0 * count(*) / current_setting('max_connections')::int, 2)::float8
+				FROM pg_catalog.pg_stat_activity
+			   WHERE backend_type = 'client backend';`
+
+type healthCheck struct {
+	name  string
+	query string
+}
+
+// healthChecks are checks of pgsql.health in the order they are run, each query returns a single value.
+var healthChecks = []healthCheck{
+	{"ping", pingQuery},
+	{"in_recovery", replicationInRecoveryQuery},
+	{"connections_pct", healthConnectionsQuery},
+	{"oldest_xid_age", oldestXIDQuery},
+	{"replication_lag", replicationLagSecQuery},
+}
+
+// healthSummary is the result of pgsql.health, the status is failed if any check has failed.
+type healthSummary struct {
+	Status string            `json:"status"`
+	Checks map[string]any    `json:"checks"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// healthHandler runs the health checks listed in the Checks parameter, all checks if it is empty, and returns
+// a summary with their values and errors.
+func healthHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	checks, err := parseHealthChecks(params["Checks"])
+	if err != nil {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(err)
+	}
+
+	summary := healthSummary{Status: healthStatusOK, Checks: make(map[string]any, len(checks))}
+
+	for _, check := range checks {
+		value, err := runHealthCheck(ctx, conn, check.query)
+		if err != nil {
+			summary.setFailed(check.name, err)
+
+			continue
+		}
+
+		summary.Checks[check.name] = value
+	}
+
+	return summary, nil
+}
+
+// connectionFailedHealth returns a summary of a server the connection to which has failed.
+func connectionFailedHealth(err error) healthSummary {
+	summary := healthSummary{Checks: map[string]any{}}
+	summary.setFailed(healthChecks[0].name, err)
+
+	return summary
+}
+
+func (s *healthSummary) setFailed(check string, err error) {
+	if s.Errors == nil {
+		s.Errors = make(map[string]string)
+	}
+
+	s.Status = healthStatusFailed
+	s.Errors[check] = err.Error()
+}
+
+// parseHealthChecks returns the checks of a comma separated list of check names in the order they are run, all
+// checks are returned if the list is empty.
+func parseHealthChecks(list string) ([]healthCheck, error) {
+	if strings.TrimSpace(list) == "" {
+		return healthChecks, nil
+	}
+
+	names := make(map[string]bool)
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if !isHealthCheck(name) {
+			return nil, errs.Errorf("unknown health check %q", name)
+		}
+
+		names[name] = true
+	}
+
+	checks := make([]healthCheck, 0, len(names))
+
+	for _, check := range healthChecks {
+		if names[check.name] {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks, nil
+}
+
+// healthQueries returns queries of all health checks.
+func healthQueries() []string {
+	queries := make([]string, 0, len(healthChecks))
+	for _, check := range healthChecks {
+		queries = append(queries, check.query)
+	}
+
+	return queries
+}
+
+func isHealthCheck(name string) bool {
+	for _, check := range healthChecks {
+		if check.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runHealthCheck executes a check query and returns its value, NULL is returned as nil.
+func runHealthCheck(ctx context.Context, conn PostgresClient, query string) (any, error) {
+	var value any
+
+	row, err := conn.QueryRow(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	err = row.Scan(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
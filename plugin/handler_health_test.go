@@ -0,0 +1,134 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_healthHandler(t *testing.T) {
+	type check struct {
+		query string
+		value any
+		err   error
+	}
+
+	tests := []struct {
+		name    string
+		checks  string
+		mock    []check
+		want    any
+		wantErr bool
+	}{
+		{
+			"+allChecks",
+			"",
+			[]check{
+				{query: pingQuery, value: int64(1)},
+				{query: replicationInRecoveryQuery, value: false},
+				{query: healthConnectionsQuery, value: 12.5},
+				{query: oldestXIDQuery, value: nil},
+				{query: replicationLagSecQuery, value: int64(0)},
+			},
+			`{"status":"ok","checks":{"connections_pct":12.5,"in_recovery":false,"oldest_xid_age":null,` +
+				`"ping":1,"replication_lag":0}}`,
+			false,
+		},
+		{
+			"+selectedChecksRunInOrder",
+			" replication_lag, ping ,replication_lag",
+			[]check{
+				{query: pingQuery, value: int64(1)},
+				{query: replicationLagSecQuery, value: int64(42)},
+			},
+			`{"status":"ok","checks":{"ping":1,"replication_lag":42}}`,
+			false,
+		},
+		{
+			"+failedCheck",
+			"in_recovery,connections_pct",
+			[]check{
+				{query: replicationInRecoveryQuery, value: true},
+				{query: healthConnectionsQuery, err: errors.New("permission denied")},
+			},
+			`{"status":"failed","checks":{"in_recovery":true},"errors":{"connections_pct":"permission denied"}}`,
+			false,
+		},
+		{
+			"-unknownCheck",
+			"ping,disk",
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			for _, c := range tt.mock {
+				if c.err != nil {
+					mock.ExpectQuery(c.query).WillReturnError(c.err)
+
+					continue
+				}
+
+				mock.ExpectQuery(c.query).WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(c.value))
+			}
+
+			got, err := healthHandler(
+				context.Background(), &PGConn{client: db}, keyHealth, map[string]string{"Checks": tt.checks},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("healthHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got, err = formatResult(keyHealth, got, false)
+			if err != nil {
+				t.Fatalf("formatResult() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("healthHandler() = %s", diff)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("healthHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
+
+func Test_connectionFailedHealth(t *testing.T) {
+	got, err := formatResult(keyHealth, connectionFailedHealth(errors.New("connection refused")), false)
+	if err != nil {
+		t.Fatalf("formatResult() error = %v", err)
+	}
+
+	want := `{"status":"failed","checks":{},"errors":{"ping":"connection refused"}}`
+	if got != want {
+		t.Errorf("connectionFailedHealth() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// idleInTxMaxAgeQuery returns the session which has been idle in transaction for the longest time, age is counted
+// from the last state change, like idle_in_transaction_session_timeout does.
+const idleInTxMaxAgeQuery = `SELECT json_build_object('age', coalesce(T.age, 0), 'pid', T.pid)
+				FROM (SELECT 1) AS D
+				LEFT JOIN (
+					SELECT
+						extract(epoch FROM clock_timestamp() - state_change)::bigint AS age,
+						pid
+					FROM pg_catalog.pg_stat_activity
+					WHERE state IN ('idle in transaction', 'idle in transaction (aborted)')
+					  AND state_change IS NOT NULL
+					ORDER BY state_change
+					LIMIT 1
+				) AS T ON TRUE;`
+
+// idleInTxMaxAgeHandler gets age in seconds and pid of the longest idle in transaction session and returns JSON
+// if all is OK or nil otherwise. Age is 0 and pid is null if there are no such sessions.
+func idleInTxMaxAgeHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var idleJSON string
+
+	row, err := conn.QueryRow(ctx, idleInTxMaxAgeQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&idleJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(idleJSON), nil
+}
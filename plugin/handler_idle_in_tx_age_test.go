@@ -0,0 +1,98 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_idleInTxMaxAgeHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		mock    mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			mock{row: sqlmock.NewRows([]string{"json"}).AddRow(`{"age" : 600, "pid" : 4321}`)},
+			jsonResult(`{"age" : 600, "pid" : 4321}`),
+			false,
+		},
+		{
+			"+noIdleInTransaction",
+			mock{row: sqlmock.NewRows([]string{"json"}).AddRow(`{"age" : 0, "pid" : null}`)},
+			jsonResult(`{"age" : 0, "pid" : null}`),
+			false,
+		},
+		{
+			"-queryErr",
+			mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			mock{row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`idle in transaction .aborted.`).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := idleInTxMaxAgeHandler(
+				context.Background(), &PGConn{client: db}, keyConnectionsIdleInTxMaxAge, nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("idleInTxMaxAgeHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("idleInTxMaxAgeHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"idleInTxMaxAgeHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithCreateIndexProgress is the first version with pg_stat_progress_create_index.
+const pgVersionWithCreateIndexProgress = 120000
+
+// indexCreateProgressQuery returns progress of index builds, percent is calculated for the current phase by blocks
+// or by tuples, depending on which of them the phase reports. Relation names are resolved in the connected
+// database only.
+const indexCreateProgressQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.pid), '[]')
+				FROM (
+					SELECT
+						p.pid,
+						p.datname,
+						CASE WHEN p.datname = current_database() THEN p.relid::regclass::text END AS relation,
+						CASE WHEN p.datname = current_database()
+							THEN nullif(p.index_relid, 0)::regclass::text END AS index,
+						p.command,
+						p.phase,
+						p.blocks_done,
+						p.blocks_total,
+						p.tuples_done,
+						p.tuples_total,
+						CASE
+							WHEN p.blocks_total > 0 THEN round(100.0 * p.blocks_done / p.blocks_total, 2)
+							WHEN p.tuples_total > 0 THEN round(100.0 * p.tuples_done / p.tuples_total, 2)
+							ELSE 0
+						END AS percent
+					  FROM pg_catalog.pg_stat_progress_create_index p
+				) T;`
+
+// indexCreateProgressHandler gets progress of CREATE INDEX and REINDEX commands and returns JSON if all is OK
+// or nil otherwise.
+func indexCreateProgressHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var progressJSON string
+
+	if conn.PostgresVersion() < pgVersionWithCreateIndexProgress {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("index build progress requires PostgreSQL %d or newer", pgVersionWithCreateIndexProgress),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, indexCreateProgressQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&progressJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(progressJSON), nil
+}
@@ -21,12 +21,7 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// locksHandler executes select from pg_stat_database command and returns JSON if all is OK or nil otherwise.
-func locksHandler(ctx context.Context, conn PostgresClient,
-	_ string, _ map[string]string, _ ...string) (any, error) {
-	var locksJSON string
-
-	query := `
+const locksQuery = `
 WITH T AS
 	(SELECT db.datname dbname,
 			lower(replace(Q.mode, 'Lock', '')) AS MODE,
@@ -74,7 +69,12 @@ FROM
 	FROM T
 	GROUP BY dbname) T2`
 
-	row, err := conn.QueryRow(ctx, query)
+// locksHandler executes select from pg_stat_database command and returns JSON if all is OK or nil otherwise.
+func locksHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var locksJSON string
+
+	row, err := conn.QueryRow(ctx, locksQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
@@ -88,5 +88,5 @@ FROM
 		return nil, errors.New("cannot parse data")
 	}
 
-	return locksJSON, nil
+	return jsonResult(locksJSON), nil
 }
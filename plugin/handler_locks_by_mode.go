@@ -0,0 +1,55 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const locksByModeQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.mode), '[]')
+				FROM (
+					SELECT mode,
+						   count(*) FILTER (WHERE granted) AS granted,
+						   count(*) FILTER (WHERE NOT granted) AS waiting
+					  FROM pg_catalog.pg_locks
+					 GROUP BY mode
+				) T;`
+
+// locksByModeHandler gets numbers of granted and waiting locks grouped by lock mode and returns JSON if all is OK
+// or nil otherwise.
+func locksByModeHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var locksJSON string
+
+	row, err := conn.QueryRow(ctx, locksByModeQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&locksJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(locksJSON), nil
+}
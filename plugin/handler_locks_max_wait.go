@@ -0,0 +1,67 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// locksMaxWaitQuery returns the lock waits query for a server version. The wait start of a lock is known since
+// PostgreSQL 14, older versions count a wait from the start of the waiting statement.
+func locksMaxWaitQuery(version int) string {
+	return resolveQuery("locks_max_wait", version)
+}
+
+// locksMaxWaitHandler gets the longest wait in seconds among waiting lock requests and numbers of all waiters and
+// of waiters longer than Threshold seconds and returns JSON if all is OK or nil otherwise.
+func locksMaxWaitHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var waitJSON string
+
+	threshold, err := strconv.Atoi(params["Threshold"])
+	if err != nil {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("Threshold must be an integer, %s", err.Error()),
+		)
+	}
+
+	if threshold < 0 {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("Threshold must not be negative"),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, locksMaxWaitQuery(conn.PostgresVersion()), threshold)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&waitJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(waitJSON), nil
+}
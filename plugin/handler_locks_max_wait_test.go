@@ -0,0 +1,134 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_locksMaxWaitHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		version   int
+		threshold string
+		mock      *mock
+		want      any
+		wantErr   bool
+	}{
+		{
+			"+valid",
+			140000,
+			"30",
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`{"max_wait" : 95, "waiters" : 3, "waiters_over_threshold" : 1}`,
+			)},
+			jsonResult(`{"max_wait" : 95, "waiters" : 3, "waiters_over_threshold" : 1}`),
+			false,
+		},
+		{
+			"+noWaiters",
+			130000,
+			"0",
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`{"max_wait" : 0, "waiters" : 0, "waiters_over_threshold" : 0}`,
+			)},
+			jsonResult(`{"max_wait" : 0, "waiters" : 0, "waiters_over_threshold" : 0}`),
+			false,
+		},
+		{
+			"-invalidThreshold",
+			140000,
+			"ten",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-negativeThreshold",
+			140000,
+			"-1",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-queryErr",
+			140000,
+			"30",
+			&mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			140000,
+			"30",
+			&mock{row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			if tt.mock != nil {
+				mock.ExpectQuery(`JOIN pg_catalog.pg_stat_activity`).
+					WithArgs(sqlmock.AnyArg()).
+					WillReturnRows(tt.mock.row).
+					WillReturnError(tt.mock.err)
+			}
+
+			got, err := locksMaxWaitHandler(
+				context.Background(),
+				&PGConn{client: db, version: tt.version},
+				keyLocksMaxWait,
+				map[string]string{"Threshold": tt.threshold},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("locksMaxWaitHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("locksMaxWaitHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"locksMaxWaitHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// longActiveCountQuery counts client backends running a query longer than $1 seconds, the monitoring backend is
+// excluded. Other backends, e.g. walsenders streaming for days, would always be counted as long active.
+const longActiveCountQuery = `SELECT count(*)
+				FROM pg_catalog.pg_stat_activity
+				WHERE state = 'active'
+				  AND query_start IS NOT NULL
+				  AND extract(epoch FROM clock_timestamp() - query_start) > $1
+				  AND backend_type = 'client backend'
+				  AND pid <> pg_catalog.pg_backend_pid();`
+
+// longActiveCountHandler gets the number of backends running a query longer than Duration seconds if all is OK or
+// nil otherwise.
+func longActiveCountHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var count int64
+
+	duration, err := strconv.Atoi(params["Duration"])
+	if err != nil {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("Duration must be an integer, %s", err.Error()),
+		)
+	}
+
+	if duration < 0 {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("Duration must not be negative"),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, longActiveCountQuery, duration)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return count, nil
+}
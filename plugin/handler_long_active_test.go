@@ -0,0 +1,131 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_longActiveCountHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		duration string
+		mock     *mock
+		want     any
+		wantErr  bool
+	}{
+		{
+			"+valid",
+			"30",
+			&mock{row: sqlmock.NewRows([]string{"count"}).AddRow(4)},
+			int64(4),
+			false,
+		},
+		{
+			// a walsender is active since replication started, it must not be counted
+			"+walsenderExcluded",
+			"30",
+			&mock{row: sqlmock.NewRows([]string{"count"}).AddRow(0)},
+			int64(0),
+			false,
+		},
+		{
+			"+zeroDuration",
+			"0",
+			&mock{row: sqlmock.NewRows([]string{"count"}).AddRow(0)},
+			int64(0),
+			false,
+		},
+		{
+			"-invalidDuration",
+			"30s",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-negativeDuration",
+			"-1",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-queryErr",
+			"30",
+			&mock{
+				row: sqlmock.NewRows([]string{"count"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			"30",
+			&mock{row: sqlmock.NewRows([]string{"count"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			if tt.mock != nil {
+				mock.ExpectQuery(`WHERE state = 'active'(.|\n)+backend_type = 'client backend'`).
+					WithArgs(sqlmock.AnyArg()).
+					WillReturnRows(tt.mock.row).
+					WillReturnError(tt.mock.err)
+			}
+
+			got, err := longActiveCountHandler(
+				context.Background(),
+				&PGConn{client: db},
+				keyActivityLongActiveCount,
+				map[string]string{"Duration": tt.duration},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("longActiveCountHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("longActiveCountHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"longActiveCountHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
@@ -22,15 +22,15 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const oldestXIDQuery = `SELECT greatest(max(age(backend_xmin)), max(age(backend_xid)))
+				FROM pg_catalog.pg_stat_activity`
+
 // oldestXIDHandler gets age of the oldest xid if all is OK or nil otherwise.
 func oldestXIDHandler(ctx context.Context, conn PostgresClient,
 	_ string, _ map[string]string, _ ...string) (any, error) {
 	var resultXID int64
 
-	query := `SELECT greatest(max(age(backend_xmin)), max(age(backend_xid)))
-				FROM pg_catalog.pg_stat_activity`
-
-	row, err := conn.QueryRow(ctx, query)
+	row, err := conn.QueryRow(ctx, oldestXIDQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
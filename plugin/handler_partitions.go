@@ -0,0 +1,97 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithPartitioning is the first version with declarative partitioning.
+const pgVersionWithPartitioning = 100000
+
+// partitionsDiscoveryQuery returns partitions of partitioned tables of the connected database with their parents,
+// a partition of a sub-partitioned table is returned with its direct parent.
+const partitionsDiscoveryQuery = `SELECT json_build_object('data', coalesce(json_agg(json_build_object(
+						'{#SCHEMA}', pn.nspname,
+						'{#PARENT}', p.relname,
+						'{#PARTITION_SCHEMA}', cn.nspname,
+						'{#PARTITION}', c.relname
+					) ORDER BY pn.nspname, p.relname, cn.nspname, c.relname), '[]'))
+				FROM pg_catalog.pg_inherits i
+				JOIN pg_catalog.pg_partitioned_table pt ON pt.partrelid = i.inhparent
+				JOIN pg_catalog.pg_class p ON p.oid = i.inhparent
+				JOIN pg_catalog.pg_namespace pn ON pn.oid = p.relnamespace
+				JOIN pg_catalog.pg_class c ON c.oid = i.inhrelid
+				JOIN pg_catalog.pg_namespace cn ON cn.oid = c.relnamespace;`
+
+// partitionsQuery returns row estimates and sizes of partitions of the connected database, rows is null for a
+// partition which has never been analyzed.
+const partitionsQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.schema, T.parent, T.partition), '[]')
+				FROM (
+					SELECT
+						pn.nspname AS schema,
+						p.relname AS parent,
+						cn.nspname AS partition_schema,
+						c.relname AS partition,
+						pg_catalog.pg_get_expr(c.relpartbound, c.oid) AS bound,
+						CASE WHEN c.reltuples < 0 THEN NULL ELSE c.reltuples::bigint END AS rows,
+						pg_catalog.pg_total_relation_size(c.oid) AS size_bytes
+					  FROM pg_catalog.pg_inherits i
+					  JOIN pg_catalog.pg_partitioned_table pt ON pt.partrelid = i.inhparent
+					  JOIN pg_catalog.pg_class p ON p.oid = i.inhparent
+					  JOIN pg_catalog.pg_namespace pn ON pn.oid = p.relnamespace
+					  JOIN pg_catalog.pg_class c ON c.oid = i.inhrelid
+					  JOIN pg_catalog.pg_namespace cn ON cn.oid = c.relnamespace
+				) T;`
+
+// partitionsQueries maps partition keys to their queries.
+var partitionsQueries = map[string]string{
+	keyPartitions:          partitionsQuery,
+	keyPartitionsDiscovery: partitionsDiscoveryQuery,
+}
+
+// partitionsHandler gets partitions of partitioned tables, a discovery rule for pgsql.partitions.discovery and
+// row estimates and sizes for pgsql.partitions, and returns JSON if all is OK or nil otherwise.
+func partitionsHandler(ctx context.Context, conn PostgresClient,
+	key string, _ map[string]string, _ ...string) (any, error) {
+	var partitionsJSON string
+
+	if conn.PostgresVersion() < pgVersionWithPartitioning {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("partitioning requires PostgreSQL %d or newer", pgVersionWithPartitioning),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, partitionsQueries[key])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&partitionsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(partitionsJSON), nil
+}
@@ -0,0 +1,137 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_partitionsHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		version int
+		mock    *mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+discovery",
+			keyPartitionsDiscovery,
+			160000,
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`{"data":[{"{#SCHEMA}":"public","{#PARENT}":"orders","{#PARTITION_SCHEMA}":"public",` +
+					`"{#PARTITION}":"orders_2026_10"}]}`,
+			)},
+			jsonResult(`{"data":[{"{#SCHEMA}":"public","{#PARENT}":"orders","{#PARTITION_SCHEMA}":"public",` +
+				`"{#PARTITION}":"orders_2026_10"}]}`),
+			false,
+		},
+		{
+			"+partitions",
+			keyPartitions,
+			100000,
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`[{"schema":"public","parent":"orders","partition_schema":"public","partition":"orders_2026_10",` +
+					`"bound":"FOR VALUES FROM ('2026-10-01') TO ('2026-11-01')","rows":null,"size_bytes":8192}]`,
+			)},
+			jsonResult(`[{"schema":"public","parent":"orders","partition_schema":"public","partition":"orders_2026_10",` +
+				`"bound":"FOR VALUES FROM ('2026-10-01') TO ('2026-11-01')","rows":null,"size_bytes":8192}]`),
+			false,
+		},
+		{
+			"+noPartitions",
+			keyPartitions,
+			160000,
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(`[]`)},
+			jsonResult(`[]`),
+			false,
+		},
+		{
+			"-unsupportedVersion",
+			keyPartitionsDiscovery,
+			96000,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-queryErr",
+			keyPartitions,
+			160000,
+			&mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			keyPartitionsDiscovery,
+			160000,
+			&mock{row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			if tt.mock != nil {
+				mock.ExpectQuery(`FROM pg_catalog.pg_inherits`).
+					WillReturnRows(tt.mock.row).
+					WillReturnError(tt.mock.err)
+			}
+
+			got, err := partitionsHandler(
+				context.Background(),
+				&PGConn{client: db, version: tt.version},
+				tt.key,
+				nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("partitionsHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("partitionsHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"partitionsHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
@@ -16,7 +16,10 @@ package plugin
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	"github.com/jackc/pgconn"
 )
 
 const (
@@ -24,12 +27,29 @@ const (
 	pingOk     = 1
 )
 
+// pingQuery returns pingOk on a live connection.
+var pingQuery = fmt.Sprintf("SELECT %d", pingOk)
+
+// SQLSTATE codes used to find out at which step a connection failed.
+const (
+	sqlStateInvalidAuthorization = "28000"
+	sqlStateInvalidPassword      = "28P01"
+	sqlStateInvalidCatalogName   = "3D000"
+)
+
+// pingDetail is a result of pgsql.ping.detail, nil means that the step was not reached.
+type pingDetail struct {
+	Reachable      bool  `json:"reachable"`
+	Authenticated  *bool `json:"authenticated"`
+	DatabaseExists *bool `json:"database_exists"`
+}
+
 // pingHandler queries 'SELECT 1' and returns pingOk if a connection is alive or pingFailed otherwise.
 func pingHandler(ctx context.Context, conn PostgresClient,
 	_ string, _ map[string]string, _ ...string) (any, error) {
 	var res int
 
-	row, err := conn.QueryRow(ctx, fmt.Sprintf("SELECT %d", pingOk))
+	row, err := conn.QueryRow(ctx, pingQuery)
 	if err != nil {
 		return pingFailed, nil
 	}
@@ -42,3 +62,39 @@ func pingHandler(ctx context.Context, conn PostgresClient,
 
 	return pingOk, nil
 }
+
+// pingDetailHandler queries 'SELECT 1' and returns JSON with connection details.
+func pingDetailHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var res int
+
+	row, err := conn.QueryRow(ctx, pingQuery)
+	if err == nil {
+		err = row.Scan(&res)
+	}
+
+	return getPingDetail(err), nil
+}
+
+// getPingDetail finds out how far a connection has got by SQLSTATE of an error.
+func getPingDetail(err error) pingDetail {
+	yes, no := true, false
+
+	if err == nil {
+		return pingDetail{Reachable: true, Authenticated: &yes, DatabaseExists: &yes}
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return pingDetail{}
+	}
+
+	switch pgErr.Code {
+	case sqlStateInvalidAuthorization, sqlStateInvalidPassword:
+		return pingDetail{Reachable: true, Authenticated: &no}
+	case sqlStateInvalidCatalogName:
+		return pingDetail{Reachable: true, Authenticated: &yes, DatabaseExists: &no}
+	default:
+		return pingDetail{Reachable: true}
+	}
+}
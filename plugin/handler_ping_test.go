@@ -19,9 +19,12 @@ package plugin
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+
+	"github.com/jackc/pgconn"
 )
 
 func TestPlugin_pingHandler(t *testing.T) {
@@ -66,3 +69,49 @@ func TestPlugin_pingHandler(t *testing.T) {
 		})
 	}
 }
+
+func Test_getPingDetail(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			"+ok",
+			nil,
+			`{"reachable":true,"authenticated":true,"database_exists":true}`,
+		},
+		{
+			"+unreachable",
+			errors.New("dial tcp 127.0.0.1:5432: connect: connection refused"),
+			`{"reachable":false,"authenticated":null,"database_exists":null}`,
+		},
+		{
+			"+authFailed",
+			fmt.Errorf("failed to connect: %w", &pgconn.PgError{Code: sqlStateInvalidPassword}),
+			`{"reachable":true,"authenticated":false,"database_exists":null}`,
+		},
+		{
+			"+noDatabase",
+			fmt.Errorf("failed to connect: %w", &pgconn.PgError{Code: sqlStateInvalidCatalogName}),
+			`{"reachable":true,"authenticated":true,"database_exists":false}`,
+		},
+		{
+			"+otherServerError",
+			&pgconn.PgError{Code: "53300"},
+			`{"reachable":true,"authenticated":null,"database_exists":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatResult(keyPingDetail, getPingDetail(tt.err), false)
+			if err != nil {
+				t.Fatalf("formatResult() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("getPingDetail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
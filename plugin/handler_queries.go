@@ -22,26 +22,14 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// queriesHandler executes select from pg_database command and returns JSON if all is OK or nil otherwise.
-func queriesHandler(ctx context.Context, conn PostgresClient,
-	_ string, params map[string]string, _ ...string) (any, error) {
-	var queriesJSON string
-
-	period, err := strconv.Atoi(params["TimePeriod"])
-	if err != nil {
-		return nil, zbxerr.ErrorInvalidParams.Wrap(
-			fmt.Errorf("TimePeriod must be an integer, %s", err.Error()),
-		)
-	}
+// maintenanceQueryExp matches queries of maintenance operations.
+const maintenanceQueryExp = `^(\\s*(--[^\\n]*\\n|/\\*.*\\*/|\\n))*(autovacuum|VACUUM|ANALYZE|REINDEX|CLUSTER|CREATE|ALTER|TRUNCATE|DROP)`
 
-	if period < 1 {
-		return nil, zbxerr.ErrorInvalidParams.Wrap(
-			fmt.Errorf("TimePeriod must be greater than 0"),
-		)
-	}
+// queriesQuery returns the queries statistic query for a slow query time limit in seconds.
+func queriesQuery(period string) string {
+	exp := maintenanceQueryExp
 
-	exp := `^(\\s*(--[^\\n]*\\n|/\\*.*\\*/|\\n))*(autovacuum|VACUUM|ANALYZE|REINDEX|CLUSTER|CREATE|ALTER|TRUNCATE|DROP)`
-	query := fmt.Sprintf(`WITH T AS (
+	return fmt.Sprintf(`WITH T AS (
 		SELECT
 			db.datname,
 			coalesce(T.query_time_max, 0) query_time_max,
@@ -156,7 +144,7 @@ func queriesHandler(ctx context.Context, conn PostgresClient,
 									'epoch'
 									FROM
 										(clock_timestamp() - query_start)
-								) > % d
+								) > %s
 							) :: integer * (
 								state NOT IN (
 									'idle',
@@ -175,7 +163,7 @@ func queriesHandler(ctx context.Context, conn PostgresClient,
 									'epoch'
 									FROM
 										(clock_timestamp() - query_start)
-								) > % d
+								) > %s
 							) :: integer * (
 								state NOT IN ('idle')
 								AND query !~* E'%s'
@@ -190,7 +178,7 @@ func queriesHandler(ctx context.Context, conn PostgresClient,
 									'epoch'
 									FROM
 										(clock_timestamp() - query_start)
-								) > % d
+								) > %s
 							) :: integer * (
 								state NOT IN ('idle')
 								AND query ~* E'%s'
@@ -213,8 +201,27 @@ func queriesHandler(ctx context.Context, conn PostgresClient,
 	FROM
 		T`,
 		exp, exp, exp, exp, exp, exp, period, exp, period, exp, period, exp)
+}
+
+// queriesHandler executes select from pg_database command and returns JSON if all is OK or nil otherwise.
+func queriesHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var queriesJSON string
+
+	period, err := strconv.Atoi(params["TimePeriod"])
+	if err != nil {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("TimePeriod must be an integer, %s", err.Error()),
+		)
+	}
+
+	if period < 1 {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("TimePeriod must be greater than 0"),
+		)
+	}
 
-	row, err := conn.QueryRow(ctx, query)
+	row, err := conn.QueryRow(ctx, queriesQuery(strconv.Itoa(period)))
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
@@ -228,5 +235,5 @@ func queriesHandler(ctx context.Context, conn PostgresClient,
 		return nil, zbxerr.ErrorCannotParseResult
 	}
 
-	return queriesJSON, nil
+	return jsonResult(queriesJSON), nil
 }
@@ -0,0 +1,167 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+)
+
+// queriesListTimePeriod replaces the TimePeriod parameter in the listed pgsql.queries SQL.
+const queriesListTimePeriod = "<TimePeriod>"
+
+// handlerQueries maps a built-in key to a function returning the SQL its handler executes on a server version.
+// Custom query keys are not listed, their SQL comes from user files, as well as keys which execute no SQL.
+var handlerQueries = map[string]func(version int) []string{
+	keyActivityLongActiveCount:         staticQueries(longActiveCountQuery),
+	keyAnalyzeProgress:                 queriesSince(pgVersionWithAnalyzeProgress, analyzeProgressQuery),
+	keyArchiveSize:                     staticQueries(archiveCountQuery, archiveSizeQuery),
+	keyArchiveLagSec:                   staticQueries(archiveLagSecQuery),
+	keyArchiveReadyCount:               queriesSince(pgVersionWithArchiveStatusDir, archiveReadyCountQuery),
+	keyAutovacuum:                      staticQueries(autovacuumQuery),
+	keyBackendsOldestQueryAge:          staticQueries(oldestQueryAgeQuery),
+	keyBgwriter:                        func(version int) []string { return []string{bgwriterQuery(version)} },
+	keyBuffercache:                     staticQueries(buffercacheInstalledQuery, buffercacheQuery),
+	keyCache:                           staticQueries(cacheHitQuery),
+	keyClusterProgress:                 queriesSince(pgVersionWithClusterProgress, clusterProgressQuery),
+	keyConnections:                     staticQueries(connectionsQuery),
+	keyConnectionsActive:               staticQueries(connectionsStateQuery),
+	keyConnectionsAvailable:            staticQueries(connectionsAvailableQuery),
+	keyConnectionsByUser:               staticQueries(connectionsByUserQuery),
+	keyConnectionsIdle:                 staticQueries(connectionsStateQuery),
+	keyConnectionsIdleInTransaction:    staticQueries(connectionsStateQuery),
+	keyConnectionsIdleInTxMaxAge:       staticQueries(idleInTxMaxAgeQuery),
+	keyDBStat:                          func(version int) []string { return []string{dbStatQuery(keyDBStat, version)} },
+	keyDBStatByDB:                      func(version int) []string { return []string{dbStatQuery(keyDBStatByDB, version)} },
+	keyDBStatSum:                       func(version int) []string { return []string{dbStatQuery(keyDBStatSum, version)} },
+	keyDatabaseAge:                     staticQueries(databaseAgeQuery),
+	keyDatabaseAgeAll:                  staticQueries(allDatabasesAgeQuery),
+	keyDatabasesBloating:               staticQueries(databasesBloatingQuery),
+	keyDatabaseBloatingByDB:            staticQueries(databaseBloatingByDBQuery),
+	keyDatabaseBloatingDiscovery:       staticQueries(databaseBloatingDiscoveryQuery),
+	keyDatabasesDiscovery:              staticQueries(databasesDiscoveryQuery),
+	keyDatabasesDiscoveryExt:           staticQueries(databasesDiscoveryExtQuery),
+	keyDatabaseObjects:                 staticQueries(databaseObjectsQuery),
+	keyDatabaseSize:                    staticQueries(databaseSizeQuery),
+	keyDatabaseSizeTS:                  staticQueries(databaseSizeTSQuery),
+	keyExtensions:                      staticQueries(extensionsQuery),
+	keyHBARules:                        queriesSince(pgVersionWithHBAFileRules, hbaRulesQuery),
+	keyHealth:                          staticQueries(healthQueries()...),
+	keyIndexCreateProgress:             queriesSince(pgVersionWithCreateIndexProgress, indexCreateProgressQuery),
+	keyIndexScans:                      staticQueries(indexScansQuery),
+	keyIndexesDiscovery:                staticQueries(indexesDiscoveryQuery),
+	keyLocks:                           staticQueries(locksQuery),
+	keyLocksByMode:                     staticQueries(locksByModeQuery),
+	keyLocksMaxWait:                    func(version int) []string { return []string{locksMaxWaitQuery(version)} },
+	keyOldestXid:                       staticQueries(oldestXIDQuery),
+	keyPartitions:                      queriesSince(pgVersionWithPartitioning, partitionsQuery),
+	keyPartitionsDiscovery:             queriesSince(pgVersionWithPartitioning, partitionsDiscoveryQuery),
+	keyPing:                            staticQueries(pingQuery),
+	keyPingDetail:                      staticQueries(pingQuery),
+	keyQueries:                         staticQueries(queriesQuery(queriesListTimePeriod)),
+	keyQueriesTopIO:                    queriesTopIOQueries,
+	keyQueryCancel:                     func(version int) []string { return []string{queryCancelQuery(version)} },
+	keyRelationDiscovery:               staticQueries(relationDiscoveryQuery),
+	keyRelationSize:                    relationSizeQueries,
+	keyReplicationCount:                staticQueries(replicationCountQuery),
+	keyReplicationCountByState:         staticQueries(replicationCountByStateQuery),
+	keyReplicationFlushLagSec:          staticQueries(replicationFlushLagSecQuery),
+	keyReplicationLagB:                 staticQueries(replicationInRecoveryQuery, replicationLagBQuery),
+	keyReplicationLagByStandby:         staticQueries(replicationLagByStandbyQuery),
+	keyReplicationLagSec:               staticQueries(replicationLagSecQuery),
+	keyReplicationPaused:               staticQueries(replicationPausedQuery),
+	keyReplicationProcessInfo:          staticQueries(replicationProcessInfoQuery),
+	keyReplicationProcessNameDiscovery: staticQueries(processNameDiscoveryQuery),
+	keyReplicationRecoveryRole:         staticQueries(replicationRecoveryRoleQuery),
+	keyReplicationReplayLagSec:         staticQueries(replicationReplayLagSecQuery),
+	keyReplicationSlotsWalStatus:       queriesSince(pgVersionWithWalStatus, replicationSlotsWalStatusQuery),
+	keyReplicationStatus:               staticQueries(replicationInRecoveryQuery, replicationWalReceiverCountQuery),
+	keyReplicationSyncState:            staticQueries(replicationSyncStateQuery),
+	keyReplicationWriteLagSec:          staticQueries(replicationWriteLagSecQuery),
+	keyRoleGrants:                      staticQueries(roleGrantsQuery),
+	keyRoles:                           staticQueries(rolesQuery),
+	keySettingsNondefault:              staticQueries(settingsNondefaultQuery),
+	keyStandbyFeedback:                 staticQueries(standbyFeedbackQuery),
+	keyStatProgressBasebackup:          queriesSince(pgVersionWithBasebackupProgress, statProgressBasebackupQuery),
+	keyStatResetTime:                   staticQueries(statResetTimeQuery),
+	keyStatSLRU:                        queriesSince(pgVersionWithStatSLRU, statSLRUQuery),
+	keyTableAnalyze:                    staticQueries(tableAnalyzeQuery),
+	keyTableVacuumAge:                  staticQueries(tableVacuumAgeQuery),
+	keyTxidCurrent:                     func(version int) []string { return []string{txidCurrentQuery(version)} },
+	keyUptime:                          staticQueries(uptimeQuery),
+	keyVersion:                         staticQueries(versionQuery),
+	keyVersionParsed:                   staticQueries(versionQuery),
+	keyWal:                             staticQueries(walLSNQuery, walFilesQueries[keyWalCount]),
+	keyWalConfig:                       staticQueries(walConfigQuery),
+	keyWalCount:                        staticQueries(walFilesQueries[keyWalCount]),
+	keyWalSize:                         staticQueries(walFilesQueries[keyWalSize]),
+	keyWraparound:                      staticQueries(wraparoundQuery),
+	keyWraparoundStatus:                staticQueries(wraparoundStatusQuery),
+}
+
+// queriesListHandler returns JSON with the SQL of every built-in key for the connected server version.
+// No query is executed, keys unsupported by the server version get an empty list.
+func queriesListHandler(_ context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	return listHandlerQueries(conn.PostgresVersion()), nil
+}
+
+// listHandlerQueries returns the SQL of every built-in key for the server version.
+func listHandlerQueries(version int) map[string][]string {
+	list := make(map[string][]string, len(handlerQueries))
+
+	for key, queries := range handlerQueries {
+		q := queries(version)
+		if q == nil {
+			q = []string{}
+		}
+
+		list[key] = q
+	}
+
+	return list
+}
+
+// staticQueries returns a function listing queries which don't depend on a server version.
+func staticQueries(queries ...string) func(int) []string {
+	return func(int) []string {
+		return queries
+	}
+}
+
+// queriesSince returns a function listing queries of a key supported since the server version, none before it.
+func queriesSince(minVersion int, queries ...string) func(int) []string {
+	return func(version int) []string {
+		if version < minVersion {
+			return nil
+		}
+
+		return queries
+	}
+}
+
+// queriesTopIOQueries returns pgsql.queries.top_io queries for the server version.
+func queriesTopIOQueries(version int) []string {
+	return []string{queriesTopIOCheckQuery, queriesTopIOQuery(version)}
+}
+
+// relationSizeQueries returns pgsql.relation.size queries for all size kinds.
+func relationSizeQueries(int) []string {
+	queries := make([]string, 0, len(relationSizeKinds))
+	for _, kind := range relationSizeKinds {
+		queries = append(queries, relationSizeQuery(relationSizeFuncs[kind]))
+	}
+
+	return queries
+}
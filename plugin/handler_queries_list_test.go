@@ -0,0 +1,112 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_handlerQueries(t *testing.T) {
+	t.Parallel()
+
+	notListed := map[string]bool{
+		keyCustomQuery:       true,
+		keyCustomQueryMulti:  true,
+		keyPluginConnections: true,
+		keyPluginLastError:   true,
+		keyQueriesList:       true,
+	}
+
+	for key := range metrics {
+		_, ok := handlerQueries[key]
+		if ok == notListed[key] {
+			t.Errorf("handlerQueries listed %v for key %q, want %v", ok, key, !ok)
+		}
+	}
+
+	for key := range handlerQueries {
+		if _, ok := metrics[key]; !ok {
+			t.Errorf("handlerQueries lists unknown key %q", key)
+		}
+	}
+}
+
+func Test_listHandlerQueries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version int
+		key     string
+		want    []string
+	}{
+		{"+bgwriterV16", 160000, keyBgwriter, []string{resolveQuery("bgwriter", 160000)}},
+		{"+bgwriterV17", 170000, keyBgwriter, []string{resolveQuery("bgwriter", 170000)}},
+		{"+dbstatSumV11", 110000, keyDBStatSum, []string{dbStatQuery(keyDBStatSum, 110000)}},
+		{"+dbstatSumV12", 120000, keyDBStatSum, []string{dbStatQuery(keyDBStatSum, 120000)}},
+		{"+walStatusV12", 120000, keyReplicationSlotsWalStatus, []string{}},
+		{"+walStatusV13", 130000, keyReplicationSlotsWalStatus, []string{replicationSlotsWalStatusQuery}},
+		{"+uptime", 170000, keyUptime, []string{uptimeQuery}},
+		{"+queries", 170000, keyQueries, []string{queriesQuery(queriesListTimePeriod)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := listHandlerQueries(tt.version)[tt.key]
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("listHandlerQueries()[%q] = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+
+	if dbStatQuery(keyDBStatSum, 110000) == dbStatQuery(keyDBStatSum, 120000) {
+		t.Fatal("dbStatQuery() does not depend on the server version")
+	}
+}
+
+func Test_queriesListHandler(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sql mock: %s", err.Error())
+	}
+
+	defer db.Close()
+
+	got, err := queriesListHandler(context.Background(), &PGConn{client: db, version: 170000}, keyQueriesList, nil)
+	if err != nil {
+		t.Fatalf("queriesListHandler() error = %v", err)
+	}
+
+	list, ok := got.(map[string][]string)
+	if !ok {
+		t.Fatalf("queriesListHandler() returned %T, want map[string][]string", got)
+	}
+
+	if !reflect.DeepEqual(list[keyVersion], []string{versionQuery}) {
+		t.Fatalf("queriesListHandler()[%q] = %v, want %v", keyVersion, list[keyVersion], []string{versionQuery})
+	}
+
+	// no query must be executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("queriesListHandler() sql mock expectations where not met: %s", err.Error())
+	}
+}
@@ -60,7 +60,7 @@ func TestPlugin_queriesHandler(t *testing.T) {
 				t.Errorf("Plugin.queriesHandler() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if len(got.(string)) == 0 && err != errors.New("cannot parse data") {
+			if len(got.(jsonResult)) == 0 && err != errors.New("cannot parse data") {
 				t.Errorf("Plugin.queriesHandler() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
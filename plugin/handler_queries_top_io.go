@@ -0,0 +1,99 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// queriesTopIOCheckQuery returns whether the pg_stat_statements extension is installed and track_io_timing is on,
+// without it I/O times of statements are always zero.
+const queriesTopIOCheckQuery = `SELECT EXISTS (
+	SELECT 1 FROM pg_catalog.pg_extension WHERE extname = 'pg_stat_statements'),
+	current_setting('track_io_timing')::bool;`
+
+// queriesTopIOQuery returns the top I/O time query for a server version. PostgreSQL 17 split blk_read_time and
+// blk_write_time of pg_stat_statements into shared and local block times.
+func queriesTopIOQuery(version int) string {
+	return resolveQuery("queries_top_io", version)
+}
+
+// queriesTopIOHandler gets statements of pg_stat_statements spending most time reading and writing blocks and
+// returns JSON if all is OK or nil otherwise. The times are accumulated since the last statistics reset.
+func queriesTopIOHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var (
+		installed    bool
+		trackIOTime  bool
+		topQueryJSON string
+	)
+
+	limit, err := strconv.Atoi(params["Limit"])
+	if err != nil {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("Limit must be an integer, %s", err.Error()),
+		)
+	}
+
+	if limit < 0 {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("Limit must not be negative"),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, queriesTopIOCheckQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&installed, &trackIOTime)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	if !installed {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(
+			errors.New("the pg_stat_statements extension is not installed in the database"),
+		)
+	}
+
+	if !trackIOTime {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(
+			errors.New("track_io_timing is off, I/O times of statements are not collected"),
+		)
+	}
+
+	row, err = conn.QueryRow(ctx, queriesTopIOQuery(conn.PostgresVersion()), limit)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&topQueryJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(topQueryJSON), nil
+}
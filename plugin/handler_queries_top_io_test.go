@@ -0,0 +1,151 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_queriesTopIOHandler(t *testing.T) {
+	type mock struct {
+		installed   bool
+		trackIOTime bool
+		row         *sqlmock.Rows
+		err         error
+	}
+
+	tests := []struct {
+		name    string
+		limit   string
+		mock    *mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			"5",
+			&mock{
+				installed:   true,
+				trackIOTime: true,
+				row: sqlmock.NewRows([]string{"json"}).AddRow(
+					`[{"datname":"app","queryid":42,"query":"SELECT * FROM t","calls":3,` +
+						`"blk_read_time":12.5,"blk_write_time":0.5,"io_time":13}]`,
+				),
+			},
+			jsonResult(`[{"datname":"app","queryid":42,"query":"SELECT * FROM t","calls":3,` +
+				`"blk_read_time":12.5,"blk_write_time":0.5,"io_time":13}]`),
+			false,
+		},
+		{
+			"+noStatements",
+			"5",
+			&mock{
+				installed:   true,
+				trackIOTime: true,
+				row:         sqlmock.NewRows([]string{"json"}).AddRow(`[]`),
+			},
+			jsonResult(`[]`),
+			false,
+		},
+		{
+			"-notInstalled",
+			"5",
+			&mock{installed: false, trackIOTime: true},
+			nil,
+			true,
+		},
+		{
+			"-trackIOTimingOff",
+			"5",
+			&mock{installed: true, trackIOTime: false},
+			nil,
+			true,
+		},
+		{
+			"-queryErr",
+			"5",
+			&mock{
+				installed:   true,
+				trackIOTime: true,
+				row:         sqlmock.NewRows([]string{"json"}),
+				err:         errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-invalidLimit",
+			"five",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-negativeLimit",
+			"-1",
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			if tt.mock != nil {
+				mock.ExpectQuery(`FROM pg_catalog.pg_extension`).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"exists", "track_io_timing"}).
+							AddRow(tt.mock.installed, tt.mock.trackIOTime),
+					)
+
+				if tt.mock.installed && tt.mock.trackIOTime {
+					mock.ExpectQuery(`FROM pg_stat_statements`).
+						WithArgs(5).
+						WillReturnRows(tt.mock.row).
+						WillReturnError(tt.mock.err)
+				}
+			}
+
+			got, err := queriesTopIOHandler(
+				context.Background(),
+				&PGConn{client: db, version: 160000},
+				keyQueriesTopIO,
+				map[string]string{"Limit": tt.limit},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("queriesTopIOHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("queriesTopIOHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("queriesTopIOHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
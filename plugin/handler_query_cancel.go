@@ -0,0 +1,52 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// queryCancelQuery returns the query canceled queries statistics for a server version, sessions killed by
+// an operator are counted since PostgreSQL 14 and logical slot conflicts since PostgreSQL 16.
+func queryCancelQuery(version int) string {
+	return resolveQuery("query_cancel", version)
+}
+
+// queryCancelHandler gets numbers of queries canceled due to recovery conflicts and of killed sessions by database
+// and returns JSON if all is OK or nil otherwise.
+func queryCancelHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var cancelJSON string
+
+	row, err := conn.QueryRow(ctx, queryCancelQuery(conn.PostgresVersion()))
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&cancelJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(cancelJSON), nil
+}
@@ -0,0 +1,127 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_queryCancelHandler(t *testing.T) {
+	type mock struct {
+		query string
+		row   *sqlmock.Rows
+		err   error
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		mock    mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			160000,
+			mock{
+				query: `confl_active_logicalslot`,
+				row: sqlmock.NewRows([]string{"json"}).AddRow(
+					`{"postgres":{"datname":"postgres","conflicts":3,"confl_tablespace":0,"confl_lock":0,` +
+						`"confl_snapshot":3,"confl_bufferpin":0,"confl_deadlock":0,"confl_active_logicalslot":0,` +
+						`"sessions_killed":1}}`,
+				),
+			},
+			jsonResult(`{"postgres":{"datname":"postgres","conflicts":3,"confl_tablespace":0,"confl_lock":0,` +
+				`"confl_snapshot":3,"confl_bufferpin":0,"confl_deadlock":0,"confl_active_logicalslot":0,` +
+				`"sessions_killed":1}}`),
+			false,
+		},
+		{
+			"+sessionsKilled",
+			140000,
+			mock{
+				query: `d.sessions_killed`,
+				row:   sqlmock.NewRows([]string{"json"}).AddRow(`{}`),
+			},
+			jsonResult(`{}`),
+			false,
+		},
+		{
+			"+conflicts",
+			100000,
+			mock{
+				query: `c.confl_deadlock\s+FROM`,
+				row:   sqlmock.NewRows([]string{"json"}).AddRow(`{}`),
+			},
+			jsonResult(`{}`),
+			false,
+		},
+		{
+			"-queryErr",
+			130000,
+			mock{
+				query: `JOIN pg_catalog.pg_stat_database_conflicts`,
+				row:   sqlmock.NewRows([]string{"json"}),
+				err:   errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			130000,
+			mock{query: `JOIN pg_catalog.pg_stat_database_conflicts`, row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(tt.mock.query).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := queryCancelHandler(
+				context.Background(),
+				&PGConn{client: db, version: tt.version},
+				keyQueryCancel,
+				nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("queryCancelHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("queryCancelHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("queryCancelHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const relationDiscoveryQuery = `SELECT json_build_object('data', coalesce(json_agg(json_build_object(
+						'{#SCHEMA}', n.nspname,
+						'{#TABLE}', c.relname,
+						'{#RELKIND}', c.relkind
+					) ORDER BY n.nspname, c.relname), '[]'))
+				FROM pg_catalog.pg_class c
+				JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			   WHERE c.relkind IN ('r', 'p', 'm')
+				 AND CASE
+						WHEN $1::text = '' THEN n.nspname NOT IN ('pg_catalog', 'information_schema')
+											AND n.nspname !~ '^pg_toast'
+						ELSE n.nspname = $1::text
+					 END
+				 AND ($2::text = '' OR c.relname ~ $2::text)
+				 AND ($3::text = '' OR c.relname !~ $3::text);`
+
+// relationDiscoveryHandler gets tables, partitioned tables and materialized views filtered by a schema and
+// include/exclude regular expressions on relation names and returns JSON if all is OK or nil otherwise.
+// System schemas are excluded unless a schema is set explicitly.
+func relationDiscoveryHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var relationsJSON string
+
+	row, err := conn.QueryRow(ctx, relationDiscoveryQuery, params["Schema"], params["Include"], params["Exclude"])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&relationsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(relationsJSON), nil
+}
@@ -0,0 +1,97 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_relationDiscoveryHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		mock    mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+noFilters",
+			map[string]string{"Schema": "", "Include": "", "Exclude": ""},
+			mock{
+				row: sqlmock.NewRows([]string{"json"}).
+					AddRow(`{"data":[{"{#SCHEMA}":"public","{#TABLE}":"orders","{#RELKIND}":"r"}]}`),
+			},
+			jsonResult(`{"data":[{"{#SCHEMA}":"public","{#TABLE}":"orders","{#RELKIND}":"r"}]}`),
+			false,
+		},
+		{
+			"+filters",
+			map[string]string{"Schema": "sales", "Include": "^order", "Exclude": "_old$"},
+			mock{row: sqlmock.NewRows([]string{"json"}).AddRow(`{"data":[]}`)},
+			jsonResult(`{"data":[]}`),
+			false,
+		},
+		{
+			"-queryErr",
+			map[string]string{"Schema": "", "Include": "(", "Exclude": ""},
+			mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("invalid regular expression"),
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM pg_catalog.pg_class c`).
+				WithArgs(tt.params["Schema"], tt.params["Include"], tt.params["Exclude"]).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := relationDiscoveryHandler(
+				context.Background(), &PGConn{client: db}, keyRelationDiscovery, tt.params,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("relationDiscoveryHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("relationDiscoveryHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("relationDiscoveryHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const (
+	sizeKindTable = "table"
+	sizeKindIndex = "index"
+	sizeKindToast = "toast"
+	sizeKindTotal = "total"
+)
+
+var (
+	relationSizeKinds = []string{sizeKindTable, sizeKindIndex, sizeKindToast, sizeKindTotal}
+
+	// relationSizeFuncs maps a size kind to an expression calculating it for pg_class row c.
+	relationSizeFuncs = map[string]string{
+		sizeKindTable: "pg_catalog.pg_table_size(c.oid)",
+		sizeKindIndex: "pg_catalog.pg_indexes_size(c.oid)",
+		sizeKindToast: "coalesce(pg_catalog.pg_total_relation_size(nullif(c.reltoastrelid, 0)), 0)",
+		sizeKindTotal: "pg_catalog.pg_total_relation_size(c.oid)",
+	}
+)
+
+// relationSizeQuery returns the query calculating a relation size with the sizeFunc expression.
+func relationSizeQuery(sizeFunc string) string {
+	return fmt.Sprintf(`SELECT %s
+				FROM pg_catalog.pg_class c
+				JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			   WHERE n.nspname = $1
+				 AND c.relname = $2;`, sizeFunc)
+}
+
+// relationSizeHandler gets size of the specific relation and returns int64 if all is OK or nil otherwise.
+func relationSizeHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var size int64
+
+	sizeFunc, ok := relationSizeFuncs[params["SizeKind"]]
+	if !ok {
+		return nil, zbxerr.ErrorInvalidParams.Wrap(
+			fmt.Errorf("unknown size kind %q", params["SizeKind"]),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, relationSizeQuery(sizeFunc), params["Schema"], params["Relation"])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&size)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return size, nil
+}
@@ -0,0 +1,134 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_relationSizeHandler(t *testing.T) {
+	type mock struct {
+		query string
+		row   *sqlmock.Rows
+		err   error
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		mock    *mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+total",
+			map[string]string{"Schema": "public", "Relation": "foo", "SizeKind": "total"},
+			&mock{
+				query: `pg_total_relation_size\(c.oid\)`,
+				row:   sqlmock.NewRows([]string{"size"}).AddRow(8192),
+			},
+			int64(8192),
+			false,
+		},
+		{
+			"+index",
+			map[string]string{"Schema": "public", "Relation": "foo", "SizeKind": "index"},
+			&mock{
+				query: `pg_indexes_size\(c.oid\)`,
+				row:   sqlmock.NewRows([]string{"size"}).AddRow(16384),
+			},
+			int64(16384),
+			false,
+		},
+		{
+			"+toast",
+			map[string]string{"Schema": "public", "Relation": "foo", "SizeKind": "toast"},
+			&mock{
+				query: `reltoastrelid`,
+				row:   sqlmock.NewRows([]string{"size"}).AddRow(0),
+			},
+			int64(0),
+			false,
+		},
+		{
+			"-unknownSizeKind",
+			map[string]string{"Schema": "public", "Relation": "foo", "SizeKind": "heap"},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"-noRelation",
+			map[string]string{"Schema": "public", "Relation": "foo", "SizeKind": "table"},
+			&mock{
+				query: `pg_table_size\(c.oid\)`,
+				row:   sqlmock.NewRows([]string{"size"}),
+			},
+			nil,
+			true,
+		},
+		{
+			"-queryErr",
+			map[string]string{"Schema": "public", "Relation": "foo", "SizeKind": "table"},
+			&mock{
+				query: `pg_table_size\(c.oid\)`,
+				row:   sqlmock.NewRows([]string{"size"}),
+				err:   errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			if tt.mock != nil {
+				mock.ExpectQuery(tt.mock.query).
+					WithArgs(tt.params["Schema"], tt.params["Relation"]).
+					WillReturnRows(tt.mock.row).
+					WillReturnError(tt.mock.err)
+			}
+
+			got, err := relationSizeHandler(
+				context.Background(), &PGConn{client: db}, keyRelationSize, tt.params,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("relationSizeHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("relationSizeHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"relationSizeHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
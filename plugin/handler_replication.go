@@ -24,6 +24,93 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const (
+	replicationInRecoveryQuery       = `SELECT pg_is_in_recovery()`
+	replicationWalReceiverCountQuery = `SELECT COUNT(*) FROM pg_stat_wal_receiver`
+	replicationRecoveryRoleQuery     = `SELECT pg_is_in_recovery()::int`
+	replicationCountQuery            = `SELECT COUNT(DISTINCT client_addr) +
+		COALESCE(SUM(CASE WHEN client_addr IS NULL THEN 1 ELSE 0 END), 0) FROM pg_stat_replication;`
+	replicationLagBQuery = `SELECT pg_catalog.pg_wal_lsn_diff
+		(pg_last_wal_receive_lsn(), pg_last_wal_replay_lsn());`
+	replicationLagSecQuery = `SELECT
+					CASE
+		  				WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		  				ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())::integer, 0)
+					END AS lag;`
+	replicationProcessInfoQuery = `SELECT json_object_agg(application_name, row_to_json(T))
+				   FROM (
+						SELECT
+						    application_name,
+							EXTRACT(epoch FROM COALESCE(flush_lag,'0'::interval)) AS flush_lag, 
+							EXTRACT(epoch FROM COALESCE(replay_lag,'0'::interval)) AS replay_lag,
+							EXTRACT(epoch FROM COALESCE(write_lag, '0'::interval)) AS write_lag
+						FROM pg_stat_replication
+					) T; `
+	replicationLagByStandbyQuery = `WITH wal AS (
+					SELECT CASE
+							WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn()
+							ELSE pg_current_wal_lsn()
+						END AS lsn
+				)
+				SELECT coalesce(json_agg(row_to_json(T)), '[]')
+				  FROM (
+						SELECT
+							application_name,
+							client_addr,
+							state,
+							pg_wal_lsn_diff(wal.lsn, sent_lsn) AS sent_lag,
+							pg_wal_lsn_diff(wal.lsn, write_lsn) AS write_lag,
+							pg_wal_lsn_diff(wal.lsn, flush_lsn) AS flush_lag,
+							pg_wal_lsn_diff(wal.lsn, replay_lsn) AS replay_lag
+						FROM pg_stat_replication, wal
+					) T;`
+	replicationPausedQuery = `SELECT
+					CASE
+						WHEN NOT pg_is_in_recovery() THEN 0
+						ELSE pg_is_wal_replay_paused()::int
+					END AS paused;`
+
+	replicationSyncStateQuery = `SELECT json_build_object(
+					'synchronous_standby_names', current_setting('synchronous_standby_names'),
+					'standbys', coalesce(json_agg(json_build_object(
+						'application_name', application_name,
+						'sync_state', sync_state,
+						'sync_priority', sync_priority
+					) ORDER BY application_name), '[]')
+				)
+				  FROM pg_catalog.pg_stat_replication;`
+
+	replicationCountByStateQuery = `SELECT json_build_object(
+					'total', (SELECT count(*) FROM pg_catalog.pg_stat_replication),
+					'state', (SELECT coalesce(json_object_agg(S.state, S.count), '{}')
+						FROM (
+							SELECT coalesce(state, 'unknown') AS state, count(*) AS count
+							  FROM pg_catalog.pg_stat_replication
+							 GROUP BY 1
+						) S),
+					'sync_state', (SELECT coalesce(json_object_agg(S.sync_state, S.count), '{}')
+						FROM (
+							SELECT coalesce(sync_state, 'unknown') AS sync_state, count(*) AS count
+							  FROM pg_catalog.pg_stat_replication
+							 GROUP BY 1
+						) S)
+				);`
+
+	replicationWriteLagSecQuery = `SELECT coalesce(max(extract(epoch FROM write_lag)), 0)
+				  FROM pg_catalog.pg_stat_replication;`
+	replicationFlushLagSecQuery = `SELECT coalesce(max(extract(epoch FROM flush_lag)), 0)
+				  FROM pg_catalog.pg_stat_replication;`
+	replicationReplayLagSecQuery = `SELECT coalesce(max(extract(epoch FROM replay_lag)), 0)
+				  FROM pg_catalog.pg_stat_replication;`
+)
+
+// replicationLagTimeQueries are queries of the time-based lags of standbys by key.
+var replicationLagTimeQueries = map[string]string{
+	keyReplicationWriteLagSec:  replicationWriteLagSecQuery,
+	keyReplicationFlushLagSec:  replicationFlushLagSecQuery,
+	keyReplicationReplayLagSec: replicationReplayLagSecQuery,
+}
+
 // replicationHandler gets info about recovery state if all is OK or nil otherwise.
 func replicationHandler(ctx context.Context, conn PostgresClient,
 	key string, _ map[string]string, _ ...string) (any, error) {
@@ -31,13 +118,12 @@ func replicationHandler(ctx context.Context, conn PostgresClient,
 		replicationResult int64
 		status            int
 		query             string
-		stringResult      sql.NullString
 		inRecovery        bool
 	)
 
 	switch key {
 	case keyReplicationStatus:
-		row, err := conn.QueryRow(ctx, `SELECT pg_is_in_recovery()`)
+		row, err := conn.QueryRow(ctx, replicationInRecoveryQuery)
 		if err != nil {
 			return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 		}
@@ -48,7 +134,7 @@ func replicationHandler(ctx context.Context, conn PostgresClient,
 		}
 
 		if inRecovery {
-			row, err = conn.QueryRow(ctx, `SELECT COUNT(*) FROM pg_stat_wal_receiver`)
+			row, err = conn.QueryRow(ctx, replicationWalReceiverCountQuery)
 			if err != nil {
 				return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 			}
@@ -68,13 +154,9 @@ func replicationHandler(ctx context.Context, conn PostgresClient,
 		return strconv.Itoa(status), nil
 
 	case keyReplicationLagSec:
-		query = `SELECT
-					CASE
-		  				WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
-		  				ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())::integer, 0)
-					END AS lag;`
+		query = replicationLagSecQuery
 	case keyReplicationLagB:
-		row, err := conn.QueryRow(ctx, `SELECT pg_is_in_recovery()`)
+		row, err := conn.QueryRow(ctx, replicationInRecoveryQuery)
 		if err != nil {
 			return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 		}
@@ -89,8 +171,7 @@ func replicationHandler(ctx context.Context, conn PostgresClient,
 		}
 
 		if inRecovery {
-			query = `SELECT pg_catalog.pg_wal_lsn_diff (pg_last_wal_receive_lsn(), pg_last_wal_replay_lsn());`
-			row, err = conn.QueryRow(ctx, query)
+			row, err = conn.QueryRow(ctx, replicationLagBQuery)
 
 			if err != nil {
 				return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
@@ -111,37 +192,36 @@ func replicationHandler(ctx context.Context, conn PostgresClient,
 		return replicationResult, nil
 
 	case keyReplicationRecoveryRole:
-		query = `SELECT pg_is_in_recovery()::int`
+		query = replicationRecoveryRoleQuery
+
+	case keyReplicationPaused:
+		query = replicationPausedQuery
 
 	case keyReplicationCount:
-		query = `SELECT COUNT(DISTINCT client_addr) + COALESCE(SUM(CASE WHEN client_addr IS NULL THEN 1 ELSE 0 END), 0) FROM pg_stat_replication;`
+		query = replicationCountQuery
+
+	case keyReplicationCountByState:
+		query = replicationCountByStateQuery
+
+		return replicationJSON(ctx, conn, query)
 
 	case keyReplicationProcessInfo:
-		query = `SELECT json_object_agg(application_name, row_to_json(T))
-				   FROM (
-						SELECT
-						    application_name,
-							EXTRACT(epoch FROM COALESCE(flush_lag,'0'::interval)) AS flush_lag, 
-							EXTRACT(epoch FROM COALESCE(replay_lag,'0'::interval)) AS replay_lag,
-							EXTRACT(epoch FROM COALESCE(write_lag, '0'::interval)) AS write_lag
-						FROM pg_stat_replication
-					) T; `
-		row, err := conn.QueryRow(ctx, query)
+		query = replicationProcessInfoQuery
 
-		if err != nil {
-			return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
-		}
+		return replicationJSON(ctx, conn, query)
 
-		err = row.Scan(&stringResult)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return nil, zbxerr.ErrorEmptyResult.Wrap(err)
-			}
+	case keyReplicationLagByStandby:
+		query = replicationLagByStandbyQuery
 
-			return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
-		}
+		return replicationJSON(ctx, conn, query)
+
+	case keyReplicationSyncState:
+		query = replicationSyncStateQuery
 
-		return stringResult.String, nil
+		return replicationJSON(ctx, conn, query)
+
+	case keyReplicationWriteLagSec, keyReplicationFlushLagSec, keyReplicationReplayLagSec:
+		return replicationLagTime(ctx, conn, replicationLagTimeQueries[key])
 	}
 
 	row, err := conn.QueryRow(ctx, query)
@@ -161,3 +241,52 @@ func replicationHandler(ctx context.Context, conn PostgresClient,
 
 	return replicationResult, nil
 }
+
+// replicationLagTime executes a query of a time-based lag and returns the largest lag among standbys in seconds,
+// 0 if there are no standbys or they are caught up. write_lag, flush_lag and replay_lag appeared in PostgreSQL 10,
+// the supported minimum, so no version check is needed.
+func replicationLagTime(ctx context.Context, conn PostgresClient, query string) (any, error) {
+	var lag float64
+
+	row, err := conn.QueryRow(ctx, query)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&lag)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return lag, nil
+}
+
+// replicationJSON executes a query returning JSON and returns it if all is OK or nil otherwise, an empty string is
+// returned if the query returns NULL.
+func replicationJSON(ctx context.Context, conn PostgresClient, query string) (any, error) {
+	var stringResult sql.NullString
+
+	row, err := conn.QueryRow(ctx, query)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&stringResult)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	if !stringResult.Valid {
+		return "", nil
+	}
+
+	return jsonResult(stringResult.String), nil
+}
@@ -22,17 +22,17 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const processNameDiscoveryQuery = `SELECT 
+	json_build_object('data',COALESCE(json_agg(json_build_object('{#APPLICATION_NAME}',application_name)), '[]'))		
+	FROM pg_stat_replication`
+
 // processNameDiscoveryHandler gets names of all sender processes in pg_stat_replication
 // and returns JSON if all is OK or nil otherwise.
 func processNameDiscoveryHandler(ctx context.Context, conn PostgresClient,
 	_ string, _ map[string]string, _ ...string) (any, error) {
 	var appNameJSON string
 
-	query := `SELECT 
-	json_build_object('data',COALESCE(json_agg(json_build_object('{#APPLICATION_NAME}',application_name)), '[]'))		
-	FROM pg_stat_replication`
-
-	row, err := conn.QueryRow(ctx, query)
+	row, err := conn.QueryRow(ctx, processNameDiscoveryQuery)
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
@@ -46,5 +46,5 @@ func processNameDiscoveryHandler(ctx context.Context, conn PostgresClient,
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
-	return appNameJSON, nil
+	return jsonResult(appNameJSON), nil
 }
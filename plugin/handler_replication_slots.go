@@ -0,0 +1,62 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithWalStatus is the first version with wal_status and safe_wal_size in pg_replication_slots.
+const pgVersionWithWalStatus = 130000
+
+const replicationSlotsWalStatusQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.slot_name), '[]')
+				FROM (
+					SELECT slot_name, slot_type, active, wal_status, safe_wal_size
+					  FROM pg_catalog.pg_replication_slots
+				) T;`
+
+// replicationSlotsWalStatusHandler gets WAL status of replication slots and returns JSON if all is OK
+// or nil otherwise.
+func replicationSlotsWalStatusHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var slotsJSON string
+
+	if conn.PostgresVersion() < pgVersionWithWalStatus {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("WAL status of replication slots requires PostgreSQL %d or newer", pgVersionWithWalStatus),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, replicationSlotsWalStatusQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&slotsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(slotsJSON), nil
+}
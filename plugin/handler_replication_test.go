@@ -49,6 +49,12 @@ func TestPlugin_replicationHandler(t *testing.T) {
 			args{context.Background(), sharedPool, keyReplicationCount, nil, []string{}},
 			false,
 		},
+		{
+			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.count.by_state"),
+			&Impl,
+			args{context.Background(), sharedPool, keyReplicationCountByState, nil, []string{}},
+			false,
+		},
 		{
 			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.status"),
 			&Impl,
@@ -73,6 +79,42 @@ func TestPlugin_replicationHandler(t *testing.T) {
 			args{context.Background(), sharedPool, keyReplicationRecoveryRole, nil, []string{}},
 			false,
 		},
+		{
+			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.lag.by_standby"),
+			&Impl,
+			args{context.Background(), sharedPool, keyReplicationLagByStandby, nil, []string{}},
+			false,
+		},
+		{
+			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.paused"),
+			&Impl,
+			args{context.Background(), sharedPool, keyReplicationPaused, nil, []string{}},
+			false,
+		},
+		{
+			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.sync_state"),
+			&Impl,
+			args{context.Background(), sharedPool, keyReplicationSyncState, nil, []string{}},
+			false,
+		},
+		{
+			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.write_lag_sec"),
+			&Impl,
+			args{context.Background(), sharedPool, keyReplicationWriteLagSec, nil, []string{}},
+			false,
+		},
+		{
+			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.flush_lag_sec"),
+			&Impl,
+			args{context.Background(), sharedPool, keyReplicationFlushLagSec, nil, []string{}},
+			false,
+		},
+		{
+			fmt.Sprintf("replicationHandler should return ptr to Pool for replication.replay_lag_sec"),
+			&Impl,
+			args{context.Background(), sharedPool, keyReplicationReplayLagSec, nil, []string{}},
+			false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -83,8 +125,9 @@ func TestPlugin_replicationHandler(t *testing.T) {
 				return
 			}
 			if tt.wantErr == false {
-				if tt.args.key == keyReplicationStatus {
-					if len(got.(string)) == 0 {
+				if tt.args.key == keyReplicationStatus || tt.args.key == keyReplicationLagByStandby ||
+					tt.args.key == keyReplicationSyncState || tt.args.key == keyReplicationCountByState {
+					if fmt.Sprint(got) == "" {
 						t.Errorf("Plugin.replicationTransactions() at DeepEqual error = %v, wantErr %v", err, tt.wantErr)
 						return
 					}
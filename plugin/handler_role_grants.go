@@ -0,0 +1,71 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// roleGrantsQuery returns whether the connected role is a superuser and whether it has privileges of the predefined
+// roles monitoring relies on. Membership is checked with USAGE, so only inherited privileges count, and a role
+// missing on the server (e.g. a PostgreSQL compatible one) is null.
+const roleGrantsQuery = `SELECT json_build_object(
+					'role', r.rolname,
+					'superuser', r.rolsuper,
+					'pg_monitor', (
+						SELECT pg_catalog.pg_has_role(r.oid, g.oid, 'USAGE')
+						  FROM pg_catalog.pg_roles g
+						 WHERE g.rolname = 'pg_monitor'
+					),
+					'pg_read_all_stats', (
+						SELECT pg_catalog.pg_has_role(r.oid, g.oid, 'USAGE')
+						  FROM pg_catalog.pg_roles g
+						 WHERE g.rolname = 'pg_read_all_stats'
+					),
+					'pg_read_all_settings', (
+						SELECT pg_catalog.pg_has_role(r.oid, g.oid, 'USAGE')
+						  FROM pg_catalog.pg_roles g
+						 WHERE g.rolname = 'pg_read_all_settings'
+					)
+				)
+				FROM pg_catalog.pg_roles r
+			   WHERE r.rolname = current_user;`
+
+// roleGrantsHandler gets the privileges of the connected role used by monitoring, so it can be told why some
+// metrics fail with permission errors, and returns JSON if all is OK or nil otherwise.
+func roleGrantsHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var grantsJSON string
+
+	row, err := conn.QueryRow(ctx, roleGrantsQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&grantsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(grantsJSON), nil
+}
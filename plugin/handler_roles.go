@@ -0,0 +1,75 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// rolesQuery returns roles with their attributes and the roles they are members of. Predefined pg_* roles are
+// skipped, and rolpassword is never selected, pg_roles masks it anyway.
+const rolesQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.rolname), '[]')
+				FROM (
+					SELECT
+						r.rolname,
+						r.rolsuper,
+						r.rolcreatedb,
+						r.rolcanlogin,
+						r.rolvaliduntil,
+						ARRAY(
+							SELECT m.rolname
+							  FROM pg_catalog.pg_auth_members a
+							  JOIN pg_catalog.pg_roles m ON m.oid = a.roleid
+							 WHERE a.member = r.oid
+							 ORDER BY m.rolname
+						) AS member_of
+					  FROM pg_catalog.pg_roles r
+					 WHERE r.rolname !~ '^pg_'
+				) T;`
+
+// rolesHandler gets roles and their attributes and returns JSON if all is OK or nil otherwise.
+func rolesHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var rolesJSON string
+
+	row, err := conn.QueryRow(ctx, rolesQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&rolesJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == sqlStateInsufficientPrivilege {
+			return nil, zbxerr.ErrorCannotFetchData.Wrap(
+				errs.Wrap(err, "roles require SELECT privilege on pg_roles and pg_auth_members"),
+			)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(rolesJSON), nil
+}
@@ -0,0 +1,134 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+)
+
+func Test_rolesHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name        string
+		version     int
+		mock        *mock
+		want        any
+		wantErr     bool
+		wantErrText string
+	}{
+		{
+			"+valid",
+			160000,
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`[{"rolname":"app","rolsuper":false,"rolcreatedb":false,"rolcanlogin":true,` +
+					`"rolvaliduntil":null,"member_of":["readers"]}]`,
+			)},
+			jsonResult(`[{"rolname":"app","rolsuper":false,"rolcreatedb":false,"rolcanlogin":true,` +
+				`"rolvaliduntil":null,"member_of":["readers"]}]`),
+			false,
+			"",
+		},
+		{
+			"+noRoles",
+			100000,
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(`[]`)},
+			jsonResult(`[]`),
+			false,
+			"",
+		},
+		{
+			"-permissionDenied",
+			160000,
+			&mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: &pgconn.PgError{Code: sqlStateInsufficientPrivilege, Message: "permission denied"},
+			},
+			nil,
+			true,
+			"require SELECT privilege",
+		},
+		{
+			"-queryErr",
+			160000,
+			&mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+			"",
+		},
+		{
+			"-noRows",
+			160000,
+			&mock{row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			if tt.mock != nil {
+				mock.ExpectQuery(`FROM pg_catalog.pg_roles`).
+					WillReturnRows(tt.mock.row).
+					WillReturnError(tt.mock.err)
+			}
+
+			got, err := rolesHandler(
+				context.Background(),
+				&PGConn{client: db, version: tt.version},
+				keyRoles,
+				nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rolesHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrText != "" && !strings.Contains(err.Error(), tt.wantErrText) {
+				t.Fatalf("rolesHandler() error = %v, want %q", err, tt.wantErrText)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("rolesHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"rolesHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
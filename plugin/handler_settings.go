@@ -0,0 +1,55 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const settingsNondefaultQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.name), '[]')
+				FROM (
+					SELECT name, setting, unit, source, sourcefile
+					  FROM pg_catalog.pg_settings
+					 WHERE setting IS DISTINCT FROM boot_val
+					   AND context <> 'internal'
+					   AND source NOT IN ('client', 'session')
+				) T;`
+
+// settingsNondefaultHandler gets settings changed from their built-in defaults and returns JSON if all is OK
+// or nil otherwise. Internal (read-only) settings and settings changed by a client session are excluded.
+func settingsNondefaultHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var settingsJSON string
+
+	row, err := conn.QueryRow(ctx, settingsNondefaultQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&settingsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(settingsJSON), nil
+}
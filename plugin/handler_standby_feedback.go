@@ -0,0 +1,62 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// standbyFeedbackQuery returns xmin horizons reported by standbys with hot_standby_feedback. A standby using
+// a replication slot reports its xmin to the slot, so backend_xmin is null and slot_xmin is set instead.
+const standbyFeedbackQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.application_name), '[]')
+				FROM (
+					SELECT
+						r.application_name,
+						r.client_addr,
+						r.state,
+						r.backend_xmin::text::bigint AS backend_xmin,
+						s.xmin::text::bigint AS slot_xmin,
+						age(coalesce(r.backend_xmin, s.xmin)) AS xmin_age,
+						coalesce(r.backend_xmin, s.xmin) IS NOT NULL AS feedback_active
+					  FROM pg_catalog.pg_stat_replication r
+					  LEFT JOIN pg_catalog.pg_replication_slots s ON s.active_pid = r.pid
+				) T;`
+
+// standbyFeedbackHandler gets xmin horizons reported by standbys to the primary and returns JSON if all is OK
+// or nil otherwise.
+func standbyFeedbackHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var feedbackJSON string
+
+	row, err := conn.QueryRow(ctx, standbyFeedbackQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&feedbackJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(feedbackJSON), nil
+}
@@ -0,0 +1,70 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithBasebackupProgress is the first version with pg_stat_progress_basebackup.
+const pgVersionWithBasebackupProgress = 130000
+
+// statProgressBasebackupQuery returns progress of base backups streamed by WAL senders, backup_total is null
+// if estimation is disabled.
+const statProgressBasebackupQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.pid), '[]')
+				FROM (
+					SELECT
+						pid,
+						phase,
+						backup_total,
+						backup_streamed,
+						tablespaces_total,
+						tablespaces_streamed
+					  FROM pg_catalog.pg_stat_progress_basebackup
+				) T;`
+
+// statProgressBasebackupHandler gets progress of running base backups and returns JSON if all is OK or nil
+// otherwise.
+func statProgressBasebackupHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var progressJSON string
+
+	if conn.PostgresVersion() < pgVersionWithBasebackupProgress {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("base backup progress requires PostgreSQL %d or newer", pgVersionWithBasebackupProgress),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, statProgressBasebackupQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&progressJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(progressJSON), nil
+}
@@ -0,0 +1,72 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// pgVersionWithStatSLRU is the first version with pg_stat_slru.
+const pgVersionWithStatSLRU = 130000
+
+// statSLRUQuery returns statistics of SLRU caches by cache name, stats_reset is in Unix epoch seconds.
+const statSLRUQuery = `SELECT coalesce(json_object_agg(T.name, row_to_json(T)), '{}')
+				FROM (
+					SELECT
+						name,
+						blks_zeroed,
+						blks_hit,
+						blks_read,
+						blks_written,
+						blks_exists,
+						flushes,
+						truncates,
+						coalesce(extract(epoch FROM stats_reset)::bigint, 0) AS stats_reset
+					  FROM pg_catalog.pg_stat_slru
+				) T;`
+
+// statSLRUHandler gets statistics of SLRU caches (subtransactions, multixacts, etc.) and returns JSON if all is OK
+// or nil otherwise.
+func statSLRUHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var slruJSON string
+
+	if conn.PostgresVersion() < pgVersionWithStatSLRU {
+		return nil, zbxerr.ErrorUnsupportedMetric.Wrap(
+			errs.Errorf("SLRU cache statistics require PostgreSQL %d or newer", pgVersionWithStatSLRU),
+		)
+	}
+
+	row, err := conn.QueryRow(ctx, statSLRUQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&slruJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(slruJSON), nil
+}
@@ -0,0 +1,58 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const tableAnalyzeQuery = `SELECT row_to_json(T)
+				FROM (
+					SELECT analyze_count,
+						   autoanalyze_count,
+						   coalesce(extract(epoch FROM last_analyze)::bigint, 0) AS last_analyze,
+						   coalesce(extract(epoch FROM last_autoanalyze)::bigint, 0) AS last_autoanalyze,
+						   n_mod_since_analyze
+					  FROM pg_catalog.pg_stat_user_tables
+					 WHERE schemaname = $1
+					   AND relname = $2
+				) T;`
+
+// tableAnalyzeHandler gets analyze statistics of the specific table and returns JSON if all is OK or nil otherwise.
+// Timestamps are returned as Unix time, 0 if the table has never been analyzed.
+func tableAnalyzeHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var analyzeJSON string
+
+	row, err := conn.QueryRow(ctx, tableAnalyzeQuery, params["Schema"], params["Relation"])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&analyzeJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(analyzeJSON), nil
+}
@@ -0,0 +1,57 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const tableVacuumAgeQuery = `SELECT row_to_json(T)
+				FROM (
+					SELECT extract(epoch FROM now() - last_vacuum)::bigint AS last_vacuum_age,
+						   extract(epoch FROM now() - last_autovacuum)::bigint AS last_autovacuum_age,
+						   extract(epoch FROM now() - last_analyze)::bigint AS last_analyze_age,
+						   extract(epoch FROM now() - last_autoanalyze)::bigint AS last_autoanalyze_age
+					  FROM pg_catalog.pg_stat_user_tables
+					 WHERE schemaname = $1
+					   AND relname = $2
+				) T;`
+
+// tableVacuumAgeHandler gets seconds since the last vacuum and analyze of the specific table and returns JSON if all
+// is OK or nil otherwise. Ages are null if the table has never been vacuumed or analyzed.
+func tableVacuumAgeHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var ageJSON string
+
+	row, err := conn.QueryRow(ctx, tableVacuumAgeQuery, params["Schema"], params["Table"])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&ageJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(ageJSON), nil
+}
@@ -0,0 +1,53 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// txidCurrentQuery returns the transaction ID query for a server version. The xid8 functions replace the txid ones
+// since PostgreSQL 13.
+func txidCurrentQuery(version int) string {
+	return resolveQuery("txid_current", version)
+}
+
+// txidCurrentHandler gets the xmin and xmax of the current snapshot and the age of its xmin as 64-bit integers and
+// returns JSON if all is OK or nil otherwise. The xmax, i.e. the next transaction ID to be assigned, is reported as
+// the current one, as getting the current transaction ID would assign one and fail on a standby server.
+func txidCurrentHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var txidJSON string
+
+	row, err := conn.QueryRow(ctx, txidCurrentQuery(conn.PostgresVersion()))
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&txidJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(txidJSON), nil
+}
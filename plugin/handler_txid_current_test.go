@@ -0,0 +1,115 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_txidCurrentHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		query   string
+		mock    *mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			160000,
+			`pg_current_snapshot\(\)`,
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`{"current" : 5000123, "xmin" : 5000100, "xmax" : 5000123, "xmin_age" : 23}`,
+			)},
+			jsonResult(`{"current" : 5000123, "xmin" : 5000100, "xmax" : 5000123, "xmin_age" : 23}`),
+			false,
+		},
+		{
+			"+txidFunctions",
+			120000,
+			`txid_current_snapshot\(\)`,
+			&mock{row: sqlmock.NewRows([]string{"json"}).AddRow(
+				`{"current" : 732, "xmin" : 731, "xmax" : 732, "xmin_age" : 1}`,
+			)},
+			jsonResult(`{"current" : 732, "xmin" : 731, "xmax" : 732, "xmin_age" : 1}`),
+			false,
+		},
+		{
+			"-queryErr",
+			160000,
+			`pg_current_snapshot\(\)`,
+			&mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("function pg_current_snapshot() does not exist"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			160000,
+			`pg_current_snapshot\(\)`,
+			&mock{row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(tt.query).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := txidCurrentHandler(
+				context.Background(),
+				&PGConn{client: db, version: tt.version},
+				keyTxidCurrent,
+				nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("txidCurrentHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("txidCurrentHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"txidCurrentHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
@@ -22,14 +22,15 @@ import (
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const uptimeQuery = `SELECT date_part('epoch', now() - pg_postmaster_start_time());`
+
 // uptimeHandler finds difference btw current time and
 // postmaster start time and returns int64 if all is OK or nil otherwise.
 func uptimeHandler(ctx context.Context, conn PostgresClient,
 	_ string, _ map[string]string, _ ...string) (any, error) {
 	var uptime float64
 
-	query := `SELECT date_part('epoch', now() - pg_postmaster_start_time());`
-	row, err := conn.QueryRow(ctx, query)
+	row, err := conn.QueryRow(ctx, uptimeQuery)
 
 	if err != nil {
 		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
@@ -0,0 +1,89 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+const (
+	indexesDiscoveryQuery = `SELECT json_build_object('data', coalesce(json_agg(json_build_object(
+						'{#SCHEMA}', schemaname,
+						'{#TABLE}', relname,
+						'{#INDEX}', indexrelname
+					) ORDER BY schemaname, relname, indexrelname), '[]'))
+				FROM pg_catalog.pg_stat_user_indexes
+			   WHERE $1::text = '' OR schemaname = $1::text;`
+
+	indexScansQuery = `SELECT row_to_json(T)
+				FROM (
+					SELECT idx_scan,
+						   idx_tup_read,
+						   idx_tup_fetch
+					  FROM pg_catalog.pg_stat_user_indexes
+					 WHERE schemaname = $1
+					   AND relname = $2
+					   AND indexrelname = $3
+				) T;`
+)
+
+// indexesDiscoveryHandler gets indexes of user tables, optionally of a single schema, and returns JSON if all is OK
+// or nil otherwise.
+func indexesDiscoveryHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var indexesJSON string
+
+	row, err := conn.QueryRow(ctx, indexesDiscoveryQuery, params["Schema"])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&indexesJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(indexesJSON), nil
+}
+
+// indexScansHandler gets scan statistics of the specific index and returns JSON if all is OK or nil otherwise.
+func indexScansHandler(ctx context.Context, conn PostgresClient,
+	_ string, params map[string]string, _ ...string) (any, error) {
+	var scansJSON string
+
+	row, err := conn.QueryRow(ctx, indexScansQuery, params["Schema"], params["Table"], params["Index"])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&scansJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(scansJSON), nil
+}
@@ -0,0 +1,174 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_indexesDiscoveryHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		schema  string
+		mock    mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+allSchemas",
+			"",
+			mock{
+				row: sqlmock.NewRows([]string{"json"}).AddRow(
+					`{"data" : [{"{#SCHEMA}" : "public", "{#TABLE}" : "orders", "{#INDEX}" : "orders_pkey"}]}`,
+				),
+			},
+			jsonResult(`{"data" : [{"{#SCHEMA}" : "public", "{#TABLE}" : "orders", "{#INDEX}" : "orders_pkey"}]}`),
+			false,
+		},
+		{
+			"+noIndexes",
+			"billing",
+			mock{row: sqlmock.NewRows([]string{"json"}).AddRow(`{"data" : []}`)},
+			jsonResult(`{"data" : []}`),
+			false,
+		},
+		{
+			"-queryErr",
+			"",
+			mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM pg_catalog.pg_stat_user_indexes`).
+				WithArgs(tt.schema).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := indexesDiscoveryHandler(
+				context.Background(),
+				&PGConn{client: db},
+				keyIndexesDiscovery,
+				map[string]string{"Schema": tt.schema},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("indexesDiscoveryHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("indexesDiscoveryHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("indexesDiscoveryHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
+
+func Test_indexScansHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		mock    mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			mock{
+				row: sqlmock.NewRows([]string{"json"}).AddRow(
+					`{"idx_scan":120,"idx_tup_read":4500,"idx_tup_fetch":4300}`,
+				),
+			},
+			jsonResult(`{"idx_scan":120,"idx_tup_read":4500,"idx_tup_fetch":4300}`),
+			false,
+		},
+		{
+			"-queryErr",
+			mock{
+				row: sqlmock.NewRows([]string{"json"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noIndex",
+			mock{row: sqlmock.NewRows([]string{"json"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM pg_catalog.pg_stat_user_indexes`).
+				WithArgs("public", "orders", "orders_pkey").
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := indexScansHandler(
+				context.Background(),
+				&PGConn{client: db},
+				keyIndexScans,
+				map[string]string{"Schema": "public", "Table": "orders", "Index": "orders_pkey"},
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("indexScansHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("indexScansHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("indexScansHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
@@ -19,10 +19,22 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v4"
-	"golang.zabbix.com/sdk/errs"
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
+const versionQuery = `SELECT version();`
+
+// serverVersionMajorDivisor splits server_version_num into major and minor versions since PostgreSQL 10.
+const serverVersionMajorDivisor = 10000
+
+// parsedVersion is a result of pgsql.version.parsed.
+type parsedVersion struct {
+	Major            int    `json:"major"`
+	Minor            int    `json:"minor"`
+	Full             string `json:"full"`
+	ServerVersionNum int    `json:"server_version_num"`
+}
+
 // versionHandler queries the version of the PostgreSQL server returns string
 // response.
 func versionHandler(
@@ -30,20 +42,52 @@ func versionHandler(
 	conn PostgresClient,
 	_ string, _ map[string]string, _ ...string,
 ) (any, error) {
+	version, err := getVersionBanner(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// versionParsedHandler queries the version banner of the PostgreSQL server and returns JSON with it and
+// with major and minor versions of the server version number.
+func versionParsedHandler(
+	ctx context.Context,
+	conn PostgresClient,
+	_ string, _ map[string]string, _ ...string,
+) (any, error) {
+	banner, err := getVersionBanner(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	version := conn.PostgresVersion()
+
+	return parsedVersion{
+		Major:            version / serverVersionMajorDivisor,
+		Minor:            version % serverVersionMajorDivisor,
+		Full:             banner,
+		ServerVersionNum: version,
+	}, nil
+}
+
+// getVersionBanner returns the result of version().
+func getVersionBanner(ctx context.Context, conn PostgresClient) (string, error) {
 	var version string
 
-	row, err := conn.QueryRow(ctx, `SELECT version();`)
+	row, err := conn.QueryRow(ctx, versionQuery)
 	if err != nil {
-		return nil, errs.Wrap(zbxerr.ErrorCannotFetchData, err.Error())
+		return "", zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
 	err = row.Scan(&version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errs.Wrap(zbxerr.ErrorEmptyResult, err.Error())
+			return "", zbxerr.ErrorEmptyResult.Wrap(err)
 		}
 
-		return nil, errs.Wrap(zbxerr.ErrorCannotFetchData, err.Error())
+		return "", zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
 	return version, nil
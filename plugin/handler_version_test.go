@@ -102,3 +102,91 @@ func Test_versionHandler(t *testing.T) {
 		})
 	}
 }
+
+func Test_versionParsedHandler(t *testing.T) {
+	type mock struct {
+		row *sqlmock.Rows
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		mock    mock
+		want    any
+		wantErr bool
+	}{
+		{
+			"+valid",
+			160002,
+			mock{
+				row: sqlmock.NewRows([]string{"version"}).AddRow("PostgreSQL 16.2 on x86_64-pc-linux-gnu"),
+			},
+			`{"major":16,"minor":2,"full":"PostgreSQL 16.2 on x86_64-pc-linux-gnu","server_version_num":160002}`,
+			false,
+		},
+		{
+			"+v10",
+			100023,
+			mock{
+				row: sqlmock.NewRows([]string{"version"}).AddRow("PostgreSQL 10.23"),
+			},
+			`{"major":10,"minor":23,"full":"PostgreSQL 10.23","server_version_num":100023}`,
+			false,
+		},
+		{
+			"-queryErr",
+			160002,
+			mock{
+				row: sqlmock.NewRows([]string{"version"}),
+				err: errors.New("query err"),
+			},
+			nil,
+			true,
+		},
+		{
+			"-noRows",
+			160002,
+			mock{row: sqlmock.NewRows([]string{"version"})},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`^SELECT version\(\);$`).
+				WillReturnRows(tt.mock.row).
+				WillReturnError(tt.mock.err)
+
+			got, err := versionParsedHandler(
+				context.Background(), &PGConn{client: db, version: tt.version}, keyVersionParsed, nil,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("versionParsedHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got, err = formatResult(keyVersionParsed, got, false)
+			if err != nil {
+				t.Fatalf("formatResult() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("versionParsedHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf(
+					"versionParsedHandler() sql mock expectations where not met: %s",
+					err.Error(),
+				)
+			}
+		})
+	}
+}
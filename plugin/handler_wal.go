@@ -17,18 +17,25 @@ package plugin
 import (
 	"context"
 	"errors"
+	"fmt"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"golang.zabbix.com/sdk/errs"
 	"golang.zabbix.com/sdk/zbxerr"
 )
 
-// walHandler executes select from directory which contains wal files and returns JSON if all is OK or nil otherwise.
-func walHandler(ctx context.Context, conn PostgresClient,
-	_ string, _ map[string]string, _ ...string) (any, error) {
-	var walJSON string
+// sqlStateInsufficientPrivilege is returned by pg_ls_waldir() and pg_ls_archive_statusdir() to users without
+// superuser or pg_monitor role and by pg_hba_file_rules to users without superuser or pg_read_all_settings role.
+const sqlStateInsufficientPrivilege = "42501"
 
-	query := `SELECT row_to_json(T)
+var walFilesQueries = map[string]string{
+	keyWalCount: `SELECT count(*) FROM pg_ls_waldir();`,
+	keyWalSize:  `SELECT coalesce(sum(size), 0)::bigint FROM pg_ls_waldir();`,
+}
+
+// walLSNQuery returns WAL positions, unlike listing the WAL directory they don't require any role.
+const walLSNQuery = `SELECT row_to_json(T)
 			    FROM (
 					SELECT
 						CASE
@@ -38,24 +45,122 @@ func walHandler(ctx context.Context, conn PostgresClient,
 						CASE 
 							WHEN NOT pg_is_in_recovery() THEN 0
 							ELSE pg_wal_lsn_diff(pg_last_wal_receive_lsn(),'0/00000000')
-						END AS RECEIVE,
-						count(*)
-						FROM pg_ls_waldir() AS COUNT
+						END AS RECEIVE
 					) T;`
 
-	row, err := conn.QueryRow(ctx, query)
+// walHandler gets WAL positions and the number of files in the WAL directory and returns JSON if all is OK or nil
+// otherwise. The positions are returned to users without permission to list the directory too, count is null and
+// count_available is false then.
+func walHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var walJSON string
+
+	row, err := conn.QueryRow(ctx, walLSNQuery)
 	if err != nil {
-		return nil, errs.Wrap(zbxerr.ErrorCannotFetchData, err.Error())
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
 	err = row.Scan(&walJSON)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errs.Wrap(zbxerr.ErrorEmptyResult, err.Error())
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	countFields, err := walCountFields(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResult(walJSON[:len(walJSON)-1] + countFields + "}"), nil
+}
+
+// walCountFields returns count and count_available fields of pgsql.wal.stat, count is null if the user has no
+// permission to list the WAL directory.
+func walCountFields(ctx context.Context, conn PostgresClient) (string, error) {
+	var count int64
+
+	row, err := conn.QueryRow(ctx, walFilesQueries[keyWalCount])
+	if err != nil {
+		return "", zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&count)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == sqlStateInsufficientPrivilege {
+			return `,"count":null,"count_available":false`, nil
+		}
+
+		return "", zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return fmt.Sprintf(`,"count":%d,"count_available":true`, count), nil
+}
+
+// walConfigQuery returns WAL settings by name, sizes are converted from their units to bytes.
+const walConfigQuery = `SELECT json_object_agg(name,
+					CASE
+						WHEN unit IS NULL THEN to_json(setting)
+						ELSE to_json(setting::bigint * CASE unit
+							WHEN '8kB' THEN 8192
+							WHEN 'kB' THEN 1024
+							WHEN 'MB' THEN 1048576
+							ELSE 1
+						END)
+					END)
+				FROM pg_catalog.pg_settings
+			   WHERE name IN ('wal_segment_size', 'min_wal_size', 'max_wal_size', 'wal_level', 'archive_mode');`
+
+// walConfigHandler gets WAL settings and returns JSON if all is OK or nil otherwise.
+func walConfigHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var configJSON string
+
+	row, err := conn.QueryRow(ctx, walConfigQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&configJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(configJSON), nil
+}
+
+// walFilesHandler returns number or total size in bytes of files in the WAL directory.
+func walFilesHandler(ctx context.Context, conn PostgresClient,
+	key string, _ map[string]string, _ ...string) (any, error) {
+	var res int64
+
+	row, err := conn.QueryRow(ctx, walFilesQueries[key])
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&res)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == sqlStateInsufficientPrivilege {
+			return nil, zbxerr.ErrorCannotFetchData.Wrap(
+				errs.Wrap(err, "pg_ls_waldir() requires superuser or pg_monitor role"),
+			)
 		}
 
-		return nil, errs.Wrap(zbxerr.ErrorCannotFetchData, err.Error())
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
 	}
 
-	return walJSON, nil
+	return res, nil
 }
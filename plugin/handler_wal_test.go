@@ -19,8 +19,14 @@ package plugin
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
 )
 
 func TestPlugin_walHandler(t *testing.T) {
@@ -59,3 +65,138 @@ func TestPlugin_walHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugin_walFilesHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{keyWalCount, keyWalSize} {
+		t.Run(key, func(t *testing.T) {
+			got, err := walFilesHandler(context.Background(), sharedPool, key, nil)
+			if err != nil {
+				t.Fatalf("Plugin.walFilesHandler() error = %v", err)
+			}
+
+			if got.(int64) < 0 {
+				t.Errorf("Plugin.walFilesHandler() = %v, want a non negative number", got)
+			}
+		})
+	}
+}
+
+func TestPlugin_walConfigHandler(t *testing.T) {
+	sharedPool, err := getConnPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := walConfigHandler(context.Background(), sharedPool, keyWalConfig, nil)
+	if err != nil {
+		t.Fatalf("Plugin.walConfigHandler() error = %v", err)
+	}
+
+	var config struct {
+		WalSegmentSize int64  `json:"wal_segment_size"`
+		MinWalSize     int64  `json:"min_wal_size"`
+		MaxWalSize     int64  `json:"max_wal_size"`
+		WalLevel       string `json:"wal_level"`
+		ArchiveMode    string `json:"archive_mode"`
+	}
+
+	err = json.Unmarshal([]byte(got.(jsonResult)), &config)
+	if err != nil {
+		t.Fatalf("Plugin.walConfigHandler() returned invalid JSON %v: %s", got, err.Error())
+	}
+
+	// the segment size is a power of 2 between 1MB and 1GB
+	if config.WalSegmentSize < 1<<20 || config.WalSegmentSize&(config.WalSegmentSize-1) != 0 {
+		t.Errorf("Plugin.walConfigHandler() wal_segment_size = %d, want a size in bytes", config.WalSegmentSize)
+	}
+
+	if config.MaxWalSize < config.MinWalSize || config.WalLevel == "" || config.ArchiveMode == "" {
+		t.Errorf("Plugin.walConfigHandler() = %v, want all settings", got)
+	}
+}
+
+func Test_walFilesHandler_permissionDenied(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sql mock: %s", err.Error())
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery(`pg_ls_waldir`).
+		WillReturnError(&pgconn.PgError{Code: sqlStateInsufficientPrivilege, Message: "permission denied"})
+
+	_, err = walFilesHandler(context.Background(), &PGConn{client: db}, keyWalCount, nil)
+	if err == nil || !strings.Contains(err.Error(), "pg_monitor") {
+		t.Fatalf("walFilesHandler() error = %v, want an error mentioning pg_monitor", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("walFilesHandler() sql mock expectations where not met: %s", err.Error())
+	}
+}
+
+func Test_walHandler_mock(t *testing.T) {
+	tests := []struct {
+		name     string
+		countErr error
+		want     any
+		wantErr  bool
+	}{
+		{
+			"+valid",
+			nil,
+			jsonResult(`{"write":1024,"receive":0,"count":3,"count_available":true}`),
+			false,
+		},
+		{
+			"+countPermissionDenied",
+			&pgconn.PgError{Code: sqlStateInsufficientPrivilege, Message: "permission denied"},
+			jsonResult(`{"write":1024,"receive":0,"count":null,"count_available":false}`),
+			false,
+		},
+		{
+			"-countErr",
+			errors.New("query err"),
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`pg_wal_lsn_diff`).
+				WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"write":1024,"receive":0}`))
+
+			if tt.countErr != nil {
+				mock.ExpectQuery(`pg_ls_waldir`).WillReturnError(tt.countErr)
+			} else {
+				mock.ExpectQuery(`pg_ls_waldir`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			}
+
+			got, err := walHandler(context.Background(), &PGConn{client: db}, keyWal, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("walHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("walHandler() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("walHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
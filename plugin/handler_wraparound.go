@@ -0,0 +1,109 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+// wraparoundQuery returns the maximum transaction ID age of databases and of tables of the connected database,
+// the percent is calculated for the 2^31 - 1 age, at which the wraparound happens.
+const wraparoundQuery = `WITH ages AS (
+					SELECT
+						(SELECT max(age(datfrozenxid)) FROM pg_catalog.pg_database) AS database_age,
+						(SELECT coalesce(max(age(relfrozenxid)), 0)
+						   FROM pg_catalog.pg_class
+						  WHERE relkind IN ('r', 'm', 't')) AS table_age
+				)
+				SELECT row_to_json(T)
+				  FROM (
+					SELECT
+						greatest(database_age, table_age) AS max_age,
+						database_age,
+						table_age,
+						current_setting('autovacuum_freeze_max_age')::bigint AS autovacuum_freeze_max_age,
+						round(100.0 * greatest(database_age, table_age) / 2147483647, 2) AS percent_towards_wraparound
+					  FROM ages
+				) T;`
+
+// wraparoundHandler gets the transaction ID wraparound risk and returns JSON if all is OK or nil otherwise.
+func wraparoundHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var wraparoundJSON string
+
+	row, err := conn.QueryRow(ctx, wraparoundQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&wraparoundJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(wraparoundJSON), nil
+}
+
+// wraparoundStatusQuery returns the transaction ID age and the wraparound severity of each database. Warning starts
+// at autovacuum_freeze_max_age, when anti-wraparound autovacuum is forced, critical at 40 million transactions
+// before the wraparound, when the server starts to warn, and emergency at 3 million transactions before it, when
+// the server refuses to assign new transaction IDs.
+const wraparoundStatusQuery = `SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.age DESC, T.datname), '[]')
+				FROM (
+					SELECT
+						datname,
+						age,
+						CASE
+							WHEN age >= 2147483647 - 3000000 THEN 'emergency'
+							WHEN age >= 2147483647 - 40000000 THEN 'critical'
+							WHEN age >= current_setting('autovacuum_freeze_max_age')::bigint THEN 'warning'
+							ELSE 'ok'
+						END AS severity
+					  FROM (
+						SELECT datname, age(datfrozenxid)::bigint AS age
+						  FROM pg_catalog.pg_database
+					  ) D
+				) T;`
+
+// wraparoundStatusHandler gets the transaction ID wraparound severity of each database and returns JSON if all is
+// OK or nil otherwise.
+func wraparoundStatusHandler(ctx context.Context, conn PostgresClient,
+	_ string, _ map[string]string, _ ...string) (any, error) {
+	var statusJSON string
+
+	row, err := conn.QueryRow(ctx, wraparoundStatusQuery)
+	if err != nil {
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	err = row.Scan(&statusJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, zbxerr.ErrorEmptyResult.Wrap(err)
+		}
+
+		return nil, zbxerr.ErrorCannotFetchData.Wrap(err)
+	}
+
+	return jsonResult(statusJSON), nil
+}
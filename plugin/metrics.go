@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -29,46 +30,131 @@ import (
 )
 
 const (
+	keyActivityLongActiveCount         = "pgsql.activity.long_active.count"
+	keyAnalyzeProgress                 = "pgsql.analyze.progress"
 	keyArchiveSize                     = "pgsql.archive"
+	keyArchiveReadyCount               = "pgsql.archive.ready_count"
+	keyArchiveLagSec                   = "pgsql.archive.lag_sec"
 	keyAutovacuum                      = "pgsql.autovacuum.count"
+	keyBackendsOldestQueryAge          = "pgsql.backends.oldest_query_age"
 	keyBgwriter                        = "pgsql.bgwriter"
+	keyBuffercache                     = "pgsql.buffercache"
 	keyCache                           = "pgsql.cache.hit"
+	keyClusterProgress                 = "pgsql.cluster.progress"
 	keyConnections                     = "pgsql.connections"
+	keyConnectionsActive               = "pgsql.connections.active"
+	keyConnectionsAvailable            = "pgsql.connections.available"
+	keyConnectionsByUser               = "pgsql.connections.by_user"
+	keyConnectionsIdle                 = "pgsql.connections.idle"
+	keyConnectionsIdleInTransaction    = "pgsql.connections.idle_in_transaction"
+	keyConnectionsIdleInTxMaxAge       = "pgsql.connections.idle_in_transaction.max_age"
 	keyCustomQuery                     = "pgsql.custom.query"
+	keyCustomQueryMulti                = "pgsql.custom.query.multi"
 	keyDBStat                          = "pgsql.dbstat"
+	keyDBStatByDB                      = "pgsql.dbstat.by_db"
 	keyDBStatSum                       = "pgsql.dbstat.sum"
 	keyDatabaseAge                     = "pgsql.db.age"
+	keyDatabaseAgeAll                  = "pgsql.db.age.all"
 	keyDatabasesBloating               = "pgsql.db.bloating_tables"
+	keyDatabaseBloatingByDB            = "pgsql.db.bloating_tables.by_db"
+	keyDatabaseBloatingDiscovery       = "pgsql.db.bloating_tables.discovery"
 	keyDatabasesDiscovery              = "pgsql.db.discovery"
+	keyDatabasesDiscoveryExt           = "pgsql.db.discovery.ext"
+	keyDatabaseObjects                 = "pgsql.db.objects"
 	keyDatabaseSize                    = "pgsql.db.size"
+	keyDatabaseSizeTS                  = "pgsql.db.size.ts"
+	keyExtensions                      = "pgsql.extensions"
+	keyHBARules                        = "pgsql.hba.rules"
+	keyHealth                          = "pgsql.health"
+	keyIndexCreateProgress             = "pgsql.index.create.progress"
+	keyIndexScans                      = "pgsql.index.scans"
+	keyIndexesDiscovery                = "pgsql.indexes.discovery"
 	keyLocks                           = "pgsql.locks"
+	keyLocksByMode                     = "pgsql.locks.by_mode"
+	keyLocksMaxWait                    = "pgsql.locks.max_wait"
 	keyOldestXid                       = "pgsql.oldest.xid"
+	keyPartitions                      = "pgsql.partitions"
+	keyPartitionsDiscovery             = "pgsql.partitions.discovery"
 	keyPing                            = "pgsql.ping"
+	keyPluginConnections               = "pgsql.plugin.connections"
+	keyPluginLastError                 = "pgsql.plugin.last_error"
+	keyPingDetail                      = "pgsql.ping.detail"
 	keyQueries                         = "pgsql.queries"
+	keyQueryCancel                     = "pgsql.query.cancel"
+	keyQueriesList                     = "pgsql.queries.list"
+	keyQueriesTopIO                    = "pgsql.queries.top_io"
+	keyRelationDiscovery               = "pgsql.relation.discovery"
+	keyRelationSize                    = "pgsql.relation.size"
 	keyReplicationCount                = "pgsql.replication.count"
+	keyReplicationCountByState         = "pgsql.replication.count.by_state"
+	keyReplicationFlushLagSec          = "pgsql.replication.flush_lag_sec"
 	keyReplicationLagB                 = "pgsql.replication.lag.b"
+	keyReplicationLagByStandby         = "pgsql.replication.lag.by_standby"
 	keyReplicationLagSec               = "pgsql.replication.lag.sec"
+	keyReplicationPaused               = "pgsql.replication.paused"
 	keyReplicationProcessInfo          = "pgsql.replication.process"
 	keyReplicationProcessNameDiscovery = "pgsql.replication.process.discovery"
 	keyReplicationRecoveryRole         = "pgsql.replication.recovery_role"
+	keyReplicationReplayLagSec         = "pgsql.replication.replay_lag_sec"
+	keyReplicationSlotsWalStatus       = "pgsql.replication.slots.wal_status"
 	keyReplicationStatus               = "pgsql.replication.status"
+	keyReplicationSyncState            = "pgsql.replication.sync_state"
+	keyReplicationWriteLagSec          = "pgsql.replication.write_lag_sec"
+	keyRoleGrants                      = "pgsql.role.grants"
+	keyRoles                           = "pgsql.roles"
+	keySettingsNondefault              = "pgsql.settings.nondefault"
+	keyStandbyFeedback                 = "pgsql.standby.feedback"
+	keyStatProgressBasebackup          = "pgsql.stat.progress.basebackup"
+	keyStatResetTime                   = "pgsql.stat.reset.time"
+	keyStatSLRU                        = "pgsql.stat.slru"
+	keyTableAnalyze                    = "pgsql.table.analyze"
+	keyTableVacuumAge                  = "pgsql.table.vacuum_age"
+	keyTxidCurrent                     = "pgsql.txid.current"
 	keyUptime                          = "pgsql.uptime"
 	keyVersion                         = "pgsql.version"
+	keyVersionParsed                   = "pgsql.version.parsed"
 	keyWal                             = "pgsql.wal.stat"
+	keyWalConfig                       = "pgsql.wal.config"
+	keyWalCount                        = "pgsql.wal.count"
+	keyWalSize                         = "pgsql.wal.size"
+	keyWraparound                      = "pgsql.wraparound"
+	keyWraparoundStatus                = "pgsql.wraparound.status"
 
-	uriParam        = "URI"
-	tcpParam        = "tcp"
-	userParam       = "User"
-	databaseParam   = "Database"
-	passwordParam   = "Password"
-	tlsConnectParam = "TLSConnect"
-	tlsCAParam      = "TLSCAFile"
-	tlsCertParam    = "TLSCertFile"
-	tlsKeyParam     = "TLSKeyFile"
-	cacheModeParam  = "CacheMode"
+	uriParam            = "URI"
+	tcpParam            = "tcp"
+	userParam           = "User"
+	databaseParam       = "Database"
+	passwordParam       = "Password"
+	tlsConnectParam     = "TLSConnect"
+	tlsCAParam          = "TLSCAFile"
+	tlsCertParam        = "TLSCertFile"
+	tlsKeyParam         = "TLSKeyFile"
+	tlsKeyPasswordParam = "TLSKeyPassword"
+	cacheModeParam      = "CacheMode"
+	assumeRoleParam     = "AssumeRole"
+	pgVersionParam      = "AssumePGVersion"
+	proxyURLParam       = "ProxyURL"
+	connectDBParam      = "ConnectDatabase"
+	callTimeoutParam    = "CallTimeout"
+	connectTimeoutParam = "ConnectTimeout"
+	outputFormatParam   = "OutputFormat"
+	clientEncodingParam = "ClientEncoding"
+	maxRowsParam        = "CustomQueriesMaxRows"
+	maxColumnsParam     = "CustomQueriesMaxColumns"
+	serviceParam        = "Service"
+	serviceFileParam    = "ServiceFile"
 )
 
-var uriDefaults = &uri.Defaults{Scheme: "tcp", Port: "5432"}
+const defaultPort = "5432"
+
+var uriDefaults = &uri.Defaults{Scheme: "tcp", Port: defaultPort}
+
+// schemeDefaultPorts are ports of URIs without a port by scheme. Unix socket URIs have no default port, the port
+// is a part of the socket file name.
+var schemeDefaultPorts = map[string]string{
+	tcpParam:     defaultPort,
+	"postgresql": defaultPort,
+}
 
 var (
 	minDBNameLen = 1
@@ -76,7 +162,10 @@ var (
 	maxPassLen   = 512
 )
 
-var reSocketPath = regexp.MustCompile(`^.*\.s\.PGSQL\.\d{1,5}$`)
+var (
+	reSocketPath = regexp.MustCompile(`^.*\.s\.PGSQL\.\d{1,5}$`)
+	reRoleName   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]{0,62}$`)
+)
 
 var (
 	paramURI = metric.NewConnParam(uriParam, "URI to connect or session name.").
@@ -98,71 +187,276 @@ var (
 	paramTLSKeyFile  = metric.NewSessionOnlyParam(tlsKeyParam, "TLS key file path.").WithDefault("")
 	paramCacheMode   = metric.NewSessionOnlyParam(cacheModeParam, "Cache mode for postgresql connections.").
 				WithDefault("prepare").
-				WithValidator(metric.SetValidator{Set: []string{"prepare", "describe"}, CaseInsensitive: false})
+				WithValidator(metric.SetValidator{Set: cacheModes, CaseInsensitive: false})
+	paramAssumeRole = metric.NewSessionOnlyParam(assumeRoleParam, "Role to switch to after connecting.").
+			WithDefault("")
+	paramAssumePGVersion = metric.NewSessionOnlyParam(pgVersionParam, "Server version to assume instead of detected.").
+				WithDefault("")
+	paramTLSKeyPassword = metric.NewSessionOnlyParam(tlsKeyPasswordParam, "Password of encrypted TLS key file.").
+				WithDefault("")
+	paramConnectDatabase = metric.NewSessionOnlyParam(connectDBParam, "Database to connect to instead of Database.").
+				WithDefault("").
+				WithValidator(metric.LenValidator{Max: &maxDBNameLen})
+	paramProxyURL  = metric.NewSessionOnlyParam(proxyURLParam, "SOCKS5 proxy URL.").WithDefault("")
 	paramQueryName = metric.NewParam(
 		"QueryName", "Name of a custom query (must be equal to a name of an SQL file without an extension).",
 	).SetRequired()
-	paramTimePeriod = metric.NewParam("TimePeriod", "Execution time limit for count of slow queries.").SetRequired()
+	paramTimePeriod = metric.NewParam("TimePeriod", "Execution time limit for count of slow queries.").
+			WithDefault("30").
+			WithValidator(metric.NumberValidator{})
+	paramSchema       = metric.NewParam("Schema", "Schema name.").SetRequired()
+	paramRelation     = metric.NewParam("Relation", "Relation (table, index, materialized view) name.").SetRequired()
+	paramSchemaFilter = metric.NewParam("Schema", "Schema name, all schemas except system ones if empty.").
+				WithDefault("")
+	paramInclude  = metric.NewParam("Include", "Regular expression relation names must match.").WithDefault("")
+	paramExclude  = metric.NewParam("Exclude", "Regular expression relation names must not match.").WithDefault("")
+	paramTopN     = metric.NewParam("TopN", "Number of relations using most buffers to return.").WithDefault("10")
+	paramSizeKind = metric.NewParam("SizeKind", "Kind of relation size: table, index, toast or total.").
+			WithDefault(sizeKindTotal).
+			WithValidator(metric.SetValidator{Set: relationSizeKinds, CaseInsensitive: false})
+	paramThreshold = metric.NewParam("Threshold", "Lock wait duration in seconds, longer waits are counted.").
+			WithDefault("0")
+	paramChecks = metric.NewParam("Checks", "Comma separated list of health checks, all checks if empty.").
+			WithDefault("")
+	paramTable = metric.NewParam("Table", "Table name.").SetRequired()
+	paramIndex = metric.NewParam("Index", "Index name.").SetRequired()
+
+	paramOutputFormat = metric.NewSessionOnlyParam(outputFormatParam, "Format of JSON results: json, csv or kv.").
+				WithDefault(outputFormatJSON).
+				WithValidator(metric.SetValidator{Set: outputFormats, CaseInsensitive: false})
+	paramClientEncoding = metric.NewSessionOnlyParam(clientEncodingParam, "Client character set encoding.").
+				WithDefault(defaultClientEncoding).
+				WithValidator(metric.SetValidator{Set: clientEncodings, CaseInsensitive: true})
+
+	paramDuration = metric.NewParam("Duration", "Query duration in seconds, longer running backends are counted.").
+			WithDefault("30").
+			WithValidator(metric.NumberValidator{})
+	paramLimit = metric.NewParam("Limit", "Number of statements to return.").
+			WithDefault("10").
+			WithValidator(metric.NumberValidator{})
+
+	paramService     = metric.NewSessionOnlyParam(serviceParam, "Service name of the service file.").WithDefault("")
+	paramServiceFile = metric.NewSessionOnlyParam(serviceFileParam, "Service file path.").WithDefault("")
 )
 
 var metrics = metric.MetricSet{
+	keyActivityLongActiveCount: metric.New(
+		"Returns number of backends running a query longer than Duration seconds.",
+		getParameters(&additionalParam{paramDuration, 4}), false,
+	),
+	keyAnalyzeProgress: metric.New(
+		"Returns JSON with progress of running ANALYZE commands.", getParameters(nil), false,
+	),
 	keyArchiveSize: metric.New(
 		"Returns info about size of archive files.", getParameters(nil), false,
 	),
+	keyArchiveLagSec: metric.New(
+		"Returns time in seconds since the last WAL file was archived.", getParameters(nil), false,
+	),
+	keyArchiveReadyCount: metric.New(
+		"Returns number of WAL segments waiting for the archiver.", getParameters(nil), false,
+	),
 	keyAutovacuum: metric.New(
 		"Returns count of autovacuum workers.", getParameters(nil), false,
 	),
+	keyBackendsOldestQueryAge: metric.New(
+		"Returns JSON with age in seconds and pid of the longest running active query.", getParameters(nil), false,
+	),
 	keyBgwriter: metric.New(
 		"Returns JSON for sum of each type of bgwriter statistic.", getParameters(nil), false,
 	),
+	keyBuffercache: metric.New(
+		"Returns JSON with shared buffers usage from the pg_buffercache extension.",
+		getParameters(&additionalParam{paramTopN, 4}), false,
+	),
 	keyCache: metric.New(
 		"Returns cache hit percent.", getParameters(nil), false,
 	),
+	keyClusterProgress: metric.New(
+		"Returns JSON with progress of running CLUSTER and VACUUM FULL commands.", getParameters(nil), false,
+	),
 	keyConnections: metric.New(
 		"Returns JSON for sum of each type of connection.", getParameters(nil), false,
 	),
+	keyConnectionsActive: metric.New(
+		"Returns number of active connections.", getParameters(nil), false,
+	),
+	keyConnectionsAvailable: metric.New(
+		"Returns number of connection slots left for ordinary users.", getParameters(nil), false,
+	),
+	keyConnectionsByUser: metric.New(
+		"Returns JSON with numbers of total, active and idle connections by user.", getParameters(nil), false,
+	),
+	keyConnectionsIdle: metric.New(
+		"Returns number of idle connections.", getParameters(nil), false,
+	),
+	keyConnectionsIdleInTransaction: metric.New(
+		"Returns number of idle in transaction connections.", getParameters(nil), false,
+	),
+	keyConnectionsIdleInTxMaxAge: metric.New(
+		"Returns JSON with age and pid of the longest idle in transaction session.", getParameters(nil), false,
+	),
 	keyCustomQuery: metric.New(
 		"Returns result of a custom query.", getParameters(&additionalParam{paramQueryName, 4}), true,
 	),
+	keyCustomQueryMulti: metric.New(
+		"Returns results of a custom query consisting of several statements.",
+		getParameters(&additionalParam{paramQueryName, 4}), true,
+	),
 	keyDBStat: metric.New(
 		"Returns JSON for sum of each type of statistic.", getParameters(nil), false,
 	),
+	keyDBStatByDB: metric.New(
+		"Returns JSON array with statistics of each database.", getParameters(nil), false,
+	),
 	keyDBStatSum: metric.New(
 		"Returns JSON for sum of each type of statistic for all database.", getParameters(nil), false,
 	),
 	keyDatabaseAge: metric.New(
 		"Returns age for specific database.", getParameters(nil), false,
 	),
+	keyDatabaseAgeAll: metric.New(
+		"Returns JSON with age of the frozen xid for all databases.", getParameters(nil), false,
+	),
 	keyDatabasesBloating: metric.New(
-		"Returns percent of bloating tables for each database.", getParameters(nil), false,
+		"Returns number of bloating tables in the connected database.", getParameters(nil), false,
+	),
+	keyDatabaseBloatingByDB: metric.New(
+		"Returns JSON with number of bloating tables and name of the connected database.", getParameters(nil), false,
+	),
+	keyDatabaseBloatingDiscovery: metric.New(
+		"Returns JSON discovery rule with bloating tables of the connected database.", getParameters(nil), false,
 	),
 	keyDatabasesDiscovery: metric.New(
 		"Returns JSON discovery rule with names of databases.", getParameters(nil), false,
 	),
+	keyDatabasesDiscoveryExt: metric.New(
+		"Returns JSON discovery rule with names, sizes and connection limits of databases.", getParameters(nil), false,
+	),
+	keyDatabaseObjects: metric.New(
+		"Returns JSON with numbers of relations by kind in the connected database.", getParameters(nil), false,
+	),
 	keyDatabaseSize: metric.New(
 		"Returns size in bytes for specific database.", getParameters(nil), false,
 	),
+	keyDatabaseSizeTS: metric.New(
+		"Returns JSON with size in bytes for specific database and server time.", getParameters(nil), false,
+	),
+	keyExtensions: metric.New(
+		"Returns JSON with installed and available extensions and their versions.", getParameters(nil), false,
+	),
+	keyHBARules: metric.New(
+		"Returns JSON with client authentication rules of pg_hba.conf.", getParameters(nil), false,
+	),
+	keyHealth: metric.New(
+		"Returns JSON with a summary of basic health checks.",
+		getParameters(&additionalParam{paramChecks, 4}), false,
+	),
+	keyIndexCreateProgress: metric.New(
+		"Returns JSON with progress of index builds.", getParameters(nil), false,
+	),
+	keyIndexScans: metric.New(
+		"Returns JSON with scan statistics of specific index.",
+		getParameters(
+			&additionalParam{paramSchema, 4},
+			&additionalParam{paramTable, 5},
+			&additionalParam{paramIndex, 6},
+		),
+		false,
+	),
+	keyIndexesDiscovery: metric.New(
+		"Returns JSON discovery rule with indexes of user tables.",
+		getParameters(&additionalParam{paramSchemaFilter, 4}), false,
+	),
 	keyLocks: metric.New(
 		"Returns collect all metrics from pg_locks.", getParameters(nil), false,
 	),
+	keyLocksByMode: metric.New(
+		"Returns JSON with numbers of granted and waiting locks by lock mode.", getParameters(nil), false,
+	),
+	keyLocksMaxWait: metric.New(
+		"Returns JSON with the longest lock wait and numbers of waiting lock requests.",
+		getParameters(&additionalParam{paramThreshold, 4}), false,
+	),
 	keyOldestXid: metric.New(
 		"Returns age of oldest xid.", getParameters(nil), false,
 	),
+	keyPartitions: metric.New(
+		"Returns JSON with row estimates and sizes of partitions of the connected database.",
+		getParameters(nil), false,
+	),
+	keyPartitionsDiscovery: metric.New(
+		"Returns JSON discovery rule with partitions of partitioned tables.", getParameters(nil), false,
+	),
 	keyPing: metric.New(
 		"Tests if connection is alive or not.", getParameters(nil), false,
 	),
+	keyPingDetail: metric.New(
+		"Returns JSON with connection details: reachability, authentication and database existence.",
+		getParameters(nil), false,
+	),
+	keyPluginConnections: metric.New(
+		"Returns JSON with cached connections of the plugin and tags of their sessions.", nil, false,
+	),
+	keyPluginLastError: metric.New(
+		"Returns JSON with the last query error of cached connections by connection address.", nil, false,
+	),
 	keyQueries: metric.New(
 		"Returns queries statistic.", getParameters(&additionalParam{paramTimePeriod, 4}), false,
 	),
+	keyQueriesList: metric.New(
+		"Returns JSON with SQL executed by built-in keys for the server version.", getParameters(nil), false,
+	),
+	keyQueriesTopIO: metric.New(
+		"Returns JSON with statements spending most time on I/O from the pg_stat_statements extension.",
+		getParameters(&additionalParam{paramLimit, 4}), false,
+	),
+	keyQueryCancel: metric.New(
+		"Returns JSON with numbers of queries canceled due to recovery conflicts by database.",
+		getParameters(nil), false,
+	),
+	keyRelationDiscovery: metric.New(
+		"Returns JSON discovery rule with relations filtered by schema and name.",
+		getParameters(
+			&additionalParam{paramSchemaFilter, 4},
+			&additionalParam{paramInclude, 5},
+			&additionalParam{paramExclude, 6},
+		),
+		false,
+	),
+	keyRelationSize: metric.New(
+		"Returns size in bytes for specific relation.",
+		getParameters(
+			&additionalParam{paramSchema, 4},
+			&additionalParam{paramRelation, 5},
+			&additionalParam{paramSizeKind, 6},
+		),
+		false,
+	),
 	keyReplicationCount: metric.New(
 		"Returns number of standby servers.", getParameters(nil), false,
 	),
+	keyReplicationCountByState: metric.New(
+		"Returns number of standby servers grouped by state and sync_state.", getParameters(nil), false,
+	),
+	keyReplicationFlushLagSec: metric.New(
+		"Returns the largest flush lag of standbys in seconds.", getParameters(nil), false,
+	),
 	keyReplicationLagB: metric.New(
 		"Returns replication lag with Master in byte.", getParameters(nil), false,
 	),
+	keyReplicationLagByStandby: metric.New(
+		"Returns JSON with replication lag in bytes per each standby.", getParameters(nil), false,
+	),
 	keyReplicationLagSec: metric.New(
 		"Returns replication lag with Master in seconds.", getParameters(nil), false,
 	),
+	keyReplicationPaused: metric.New(
+		"Returns 1 if WAL replay is paused on a standby, 0 otherwise.", getParameters(nil), false,
+	),
+	keyReplicationSlotsWalStatus: metric.New(
+		"Returns JSON with WAL status of replication slots.", getParameters(nil), false,
+	),
 	keyReplicationProcessNameDiscovery: metric.New(
 		"Returns JSON with application name from pg_stat_replication.", getParameters(nil), false,
 	),
@@ -172,18 +466,87 @@ var metrics = metric.MetricSet{
 	keyReplicationRecoveryRole: metric.New(
 		"Returns postgreSQL recovery role.", getParameters(nil), false,
 	),
+	keyReplicationReplayLagSec: metric.New(
+		"Returns the largest replay lag of standbys in seconds.", getParameters(nil), false,
+	),
 	keyReplicationStatus: metric.New(
 		"Returns postgreSQL replication status.", getParameters(nil), false,
 	),
+	keyReplicationSyncState: metric.New(
+		"Returns JSON with synchronous states of standbys and synchronous_standby_names.", getParameters(nil), false,
+	),
+	keyReplicationWriteLagSec: metric.New(
+		"Returns the largest write lag of standbys in seconds.", getParameters(nil), false,
+	),
+	keyRoleGrants: metric.New(
+		"Returns JSON with superuser status and memberships of the connected role in monitoring roles.",
+		getParameters(nil), false,
+	),
+	keyRoles: metric.New(
+		"Returns JSON with roles, their attributes and memberships.", getParameters(nil), false,
+	),
+	keySettingsNondefault: metric.New(
+		"Returns JSON with settings changed from their defaults.", getParameters(nil), false,
+	),
+	keyStandbyFeedback: metric.New(
+		"Returns JSON with xmin horizons reported by standbys with hot_standby_feedback.", getParameters(nil), false,
+	),
+	keyStatProgressBasebackup: metric.New(
+		"Returns JSON with progress of running base backups.", getParameters(nil), false,
+	),
+	keyStatResetTime: metric.New(
+		"Returns time of the latest statistics reset in Unix epoch seconds.", getParameters(nil), false,
+	),
+	keyStatSLRU: metric.New(
+		"Returns JSON with statistics of SLRU caches.", getParameters(nil), false,
+	),
+	keyTableAnalyze: metric.New(
+		"Returns JSON with analyze and autoanalyze statistics for specific table.",
+		getParameters(
+			&additionalParam{paramSchema, 4},
+			&additionalParam{paramRelation, 5},
+		),
+		false,
+	),
+	keyTableVacuumAge: metric.New(
+		"Returns JSON with seconds since the last vacuum and analyze of specific table.",
+		getParameters(
+			&additionalParam{paramSchema, 4},
+			&additionalParam{paramTable, 5},
+		),
+		false,
+	),
+	keyTxidCurrent: metric.New(
+		"Returns JSON with the current transaction ID and xmin and xmax of the current snapshot.",
+		getParameters(nil), false,
+	),
 	keyUptime: metric.New(
 		"Returns uptime.", getParameters(nil), false,
 	),
 	keyVersion: metric.New(
 		"Returns PostgreSQL version.", getParameters(nil), false,
 	),
+	keyVersionParsed: metric.New(
+		"Returns JSON with PostgreSQL version split into components.", getParameters(nil), false,
+	),
 	keyWal: metric.New(
 		"Returns JSON wal by type.", getParameters(nil), false,
 	),
+	keyWalConfig: metric.New(
+		"Returns JSON with WAL settings, sizes are in bytes.", getParameters(nil), false,
+	),
+	keyWalCount: metric.New(
+		"Returns number of files in the WAL directory.", getParameters(nil), false,
+	),
+	keyWalSize: metric.New(
+		"Returns total size of files in the WAL directory in bytes.", getParameters(nil), false,
+	),
+	keyWraparound: metric.New(
+		"Returns JSON with the transaction ID wraparound risk.", getParameters(nil), false,
+	),
+	keyWraparoundStatus: metric.New(
+		"Returns JSON with the transaction ID wraparound severity of each database.", getParameters(nil), false,
+	),
 }
 
 func init() { //todo remove init and global variable Impl
@@ -216,51 +579,154 @@ type additionalParam struct {
 // getHandlerFunc returns a handlerFunc related to a given key.
 func getHandlerFunc(key string) handlerFunc {
 	switch key {
+	case keyActivityLongActiveCount:
+		return longActiveCountHandler
+	case keyAnalyzeProgress:
+		return analyzeProgressHandler
+	case keyArchiveLagSec:
+		return archiveLagSecHandler
+	case keyArchiveReadyCount:
+		return archiveReadyCountHandler
 	case keyArchiveSize:
 		return archiveHandler
 	case keyAutovacuum:
 		return autovacuumHandler
+	case keyBackendsOldestQueryAge:
+		return oldestQueryAgeHandler
 	case keyBgwriter:
 		return bgwriterHandler
+	case keyBuffercache:
+		return buffercacheHandler
 	case keyCache:
 		return cacheHandler
+	case keyClusterProgress:
+		return clusterProgressHandler
 	case keyConnections:
 		return connectionsHandler
+	case keyConnectionsAvailable:
+		return connectionsAvailableHandler
+	case keyConnectionsByUser:
+		return connectionsByUserHandler
+	case keyConnectionsActive, keyConnectionsIdle, keyConnectionsIdleInTransaction:
+		return connectionsStateHandler
+	case keyConnectionsIdleInTxMaxAge:
+		return idleInTxMaxAgeHandler
 	case keyCustomQuery:
 		return customQueryHandler
-	case keyDBStat, keyDBStatSum:
+	case keyCustomQueryMulti:
+		return customQueryMultiHandler
+	case keyDBStat, keyDBStatByDB, keyDBStatSum:
 		return dbStatHandler
 	case keyDatabaseAge:
 		return databaseAgeHandler
+	case keyDatabaseAgeAll:
+		return allDatabasesAgeHandler
 	case keyDatabasesBloating:
 		return databasesBloatingHandler
-	case keyDatabasesDiscovery:
+	case keyDatabaseBloatingByDB:
+		return databaseBloatingByDBHandler
+	case keyDatabaseBloatingDiscovery:
+		return databaseBloatingDiscoveryHandler
+	case keyDatabasesDiscovery, keyDatabasesDiscoveryExt:
 		return databasesDiscoveryHandler
+	case keyDatabaseObjects:
+		return databaseObjectsHandler
 	case keyDatabaseSize:
 		return databaseSizeHandler
+	case keyDatabaseSizeTS:
+		return databaseSizeTSHandler
+	case keyExtensions:
+		return extensionsHandler
+	case keyHBARules:
+		return hbaRulesHandler
+	case keyHealth:
+		return healthHandler
+	case keyIndexCreateProgress:
+		return indexCreateProgressHandler
+	case keyIndexScans:
+		return indexScansHandler
+	case keyIndexesDiscovery:
+		return indexesDiscoveryHandler
 	case keyLocks:
 		return locksHandler
+	case keyLocksByMode:
+		return locksByModeHandler
+	case keyLocksMaxWait:
+		return locksMaxWaitHandler
 	case keyOldestXid:
 		return oldestXIDHandler
+	case keyPartitions, keyPartitionsDiscovery:
+		return partitionsHandler
 	case keyPing:
 		return pingHandler
+	case keyPingDetail:
+		return pingDetailHandler
 	case keyQueries:
 		return queriesHandler
+	case keyQueriesList:
+		return queriesListHandler
+	case keyQueriesTopIO:
+		return queriesTopIOHandler
+	case keyQueryCancel:
+		return queryCancelHandler
+	case keyRelationDiscovery:
+		return relationDiscoveryHandler
+	case keyRelationSize:
+		return relationSizeHandler
 	case keyReplicationCount,
+		keyReplicationCountByState,
 		keyReplicationLagB,
+		keyReplicationLagByStandby,
 		keyReplicationLagSec,
+		keyReplicationPaused,
 		keyReplicationProcessInfo,
 		keyReplicationRecoveryRole,
-		keyReplicationStatus:
+		keyReplicationStatus,
+		keyReplicationSyncState,
+		keyReplicationWriteLagSec,
+		keyReplicationFlushLagSec,
+		keyReplicationReplayLagSec:
 		return replicationHandler
+	case keyReplicationSlotsWalStatus:
+		return replicationSlotsWalStatusHandler
 	case keyReplicationProcessNameDiscovery:
 		return processNameDiscoveryHandler
+	case keyRoleGrants:
+		return roleGrantsHandler
+	case keyRoles:
+		return rolesHandler
+	case keySettingsNondefault:
+		return settingsNondefaultHandler
+	case keyStandbyFeedback:
+		return standbyFeedbackHandler
+	case keyStatProgressBasebackup:
+		return statProgressBasebackupHandler
+	case keyStatResetTime:
+		return statResetTimeHandler
+	case keyStatSLRU:
+		return statSLRUHandler
+	case keyTableAnalyze:
+		return tableAnalyzeHandler
+	case keyTableVacuumAge:
+		return tableVacuumAgeHandler
+	case keyTxidCurrent:
+		return txidCurrentHandler
 	case keyUptime:
 		return uptimeHandler
 	case keyVersion:
 		return versionHandler
+	case keyVersionParsed:
+		return versionParsedHandler
 	case keyWal:
 		return walHandler
+	case keyWalConfig:
+		return walConfigHandler
+	case keyWalCount, keyWalSize:
+		return walFilesHandler
+	case keyWraparound:
+		return wraparoundHandler
+	case keyWraparoundStatus:
+		return wraparoundStatusHandler
 	default:
 		return nil
 	}
@@ -271,7 +737,7 @@ func (v PostgresURIValidator) Validate(value *string) error {
 		return nil
 	}
 
-	u, err := uri.New(*value, v.Defaults)
+	u, err := newURI(*value, "", "", v.Defaults)
 	if err != nil {
 		return errs.Wrap(err, "cannot create URI validator")
 	}
@@ -291,15 +757,50 @@ func (v PostgresURIValidator) Validate(value *string) error {
 		}
 	}
 
-	if u.Scheme() == "unix" && !reSocketPath.MatchString(*value) {
-		return errors.New(
-			`socket file must satisfy the format: "/path/.s.PGSQL.nnnn" where nnnn is the server's port number`)
+	if u.Scheme() == "unix" {
+		if !reSocketPath.MatchString(*value) {
+			return errors.New(
+				`socket file must satisfy the format: "/path/.s.PGSQL.nnnn" where nnnn is the server's port number`)
+		}
+
+		err = validatePort(filepath.Ext(*value)[1:])
+		if err != nil {
+			return errs.Wrap(err, "invalid socket file")
+		}
+
+		return nil
+	}
+
+	err = validatePort(u.Port())
+	if err != nil {
+		return errs.Wrap(err, "invalid URI port")
 	}
 
 	return nil
 }
 
-func getParameters(add *additionalParam) []*metric.Param {
+// newURI parses a connection URI, the default scheme is taken from defaults and a URI without a port gets
+// the default port of its scheme.
+func newURI(rawURI, user, password string, defaults *uri.Defaults) (*uri.URI, error) {
+	// Parse errors contain the URI, which may have a password.
+	u, err := uri.New(rawURI, &uri.Defaults{Scheme: defaults.Scheme})
+	if err != nil {
+		return nil, errs.Errorf("cannot parse URI: %s", redactDSN(err.Error()))
+	}
+
+	u, err = uri.NewWithCreds(
+		rawURI, user, password, &uri.Defaults{Scheme: defaults.Scheme, Port: schemeDefaultPorts[u.Scheme()]},
+	)
+	if err != nil {
+		return nil, errs.Errorf("cannot parse URI: %s", redactDSN(err.Error()))
+	}
+
+	return u, nil
+}
+
+// getParameters returns common parameters with additional ones inserted at their positions,
+// additional parameters must be sorted by position.
+func getParameters(add ...*additionalParam) []*metric.Param {
 	m := []*metric.Param{
 		paramURI,
 		paramUsername,
@@ -309,12 +810,25 @@ func getParameters(add *additionalParam) []*metric.Param {
 		paramTLSCaFile,
 		paramTLSCertFile,
 		paramTLSKeyFile,
+		paramTLSKeyPassword,
 		paramCacheMode,
+		paramAssumeRole,
+		paramAssumePGVersion,
+		paramProxyURL,
+		paramConnectDatabase,
+		paramOutputFormat,
+		paramClientEncoding,
+		paramService,
+		paramServiceFile,
 	}
 
-	if add != nil && add.param != nil {
-		m = append(m[:add.position+1], m[add.position:]...)
-		m[add.position] = add.param
+	for _, a := range add {
+		if a == nil || a.param == nil {
+			continue
+		}
+
+		m = append(m[:a.position+1], m[a.position:]...)
+		m[a.position] = a.param
 	}
 
 	return m
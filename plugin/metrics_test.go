@@ -25,7 +25,7 @@ import (
 
 func Test_getParameters(t *testing.T) {
 	type args struct {
-		additional *additionalParam
+		additional []*additionalParam
 	}
 
 	tests := []struct {
@@ -35,7 +35,7 @@ func Test_getParameters(t *testing.T) {
 	}{
 		{
 			"common parameters",
-			args{nil},
+			args{[]*additionalParam{nil}},
 			[]*metric.Param{
 				paramURI,
 				paramUsername,
@@ -45,12 +45,21 @@ func Test_getParameters(t *testing.T) {
 				paramTLSCaFile,
 				paramTLSCertFile,
 				paramTLSKeyFile,
+				paramTLSKeyPassword,
 				paramCacheMode,
+				paramAssumeRole,
+				paramAssumePGVersion,
+				paramProxyURL,
+				paramConnectDatabase,
+				paramOutputFormat,
+				paramClientEncoding,
+				paramService,
+				paramServiceFile,
 			},
 		},
 		{
 			"empty additions map",
-			args{&additionalParam{}},
+			args{[]*additionalParam{{}}},
 			[]*metric.Param{
 				paramURI,
 				paramUsername,
@@ -60,15 +69,26 @@ func Test_getParameters(t *testing.T) {
 				paramTLSCaFile,
 				paramTLSCertFile,
 				paramTLSKeyFile,
+				paramTLSKeyPassword,
 				paramCacheMode,
+				paramAssumeRole,
+				paramAssumePGVersion,
+				paramProxyURL,
+				paramConnectDatabase,
+				paramOutputFormat,
+				paramClientEncoding,
+				paramService,
+				paramServiceFile,
 			},
 		},
 		{
 			"with additional parameter",
 			args{
-				&additionalParam{
-					param:    metric.NewParam("test", "Foo bar."),
-					position: 4,
+				[]*additionalParam{
+					{
+						param:    metric.NewParam("test", "Foo bar."),
+						position: 4,
+					},
 				},
 			},
 			[]*metric.Param{
@@ -81,13 +101,59 @@ func Test_getParameters(t *testing.T) {
 				paramTLSCaFile,
 				paramTLSCertFile,
 				paramTLSKeyFile,
+				paramTLSKeyPassword,
 				paramCacheMode,
+				paramAssumeRole,
+				paramAssumePGVersion,
+				paramProxyURL,
+				paramConnectDatabase,
+				paramOutputFormat,
+				paramClientEncoding,
+				paramService,
+				paramServiceFile,
+			},
+		},
+		{
+			"with several additional parameters",
+			args{
+				[]*additionalParam{
+					{
+						param:    metric.NewParam("foo", "Foo."),
+						position: 4,
+					},
+					{
+						param:    metric.NewParam("bar", "Bar."),
+						position: 5,
+					},
+				},
+			},
+			[]*metric.Param{
+				paramURI,
+				paramUsername,
+				paramPassword,
+				paramDatabase,
+				metric.NewParam("foo", "Foo."),
+				metric.NewParam("bar", "Bar."),
+				paramTLSConnect,
+				paramTLSCaFile,
+				paramTLSCertFile,
+				paramTLSKeyFile,
+				paramTLSKeyPassword,
+				paramCacheMode,
+				paramAssumeRole,
+				paramAssumePGVersion,
+				paramProxyURL,
+				paramConnectDatabase,
+				paramOutputFormat,
+				paramClientEncoding,
+				paramService,
+				paramServiceFile,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getParameters(tt.args.additional); !reflect.DeepEqual(got, tt.want) {
+			if got := getParameters(tt.args.additional...); !reflect.DeepEqual(got, tt.want) {
 				var gotString, wantString string
 
 				for _, v := range got {
@@ -103,3 +169,111 @@ func Test_getParameters(t *testing.T) {
 		})
 	}
 }
+
+func Test_queriesTimePeriod(t *testing.T) {
+	tests := []struct {
+		name      string
+		rawParams []string
+		want      string
+		wantErr   bool
+	}{
+		{"+default", []string{"tcp://localhost"}, "30", false},
+		{"+emptyDefault", []string{"tcp://localhost", "", "", "", ""}, "30", false},
+		{"+explicit", []string{"tcp://localhost", "", "", "", "60"}, "60", false},
+		{"-notNumber", []string{"tcp://localhost", "", "", "", "1m"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _, _, err := metrics[keyQueries].EvalParams(tt.rawParams, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvalParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := params["TimePeriod"]; got != tt.want {
+				t.Errorf("EvalParams() TimePeriod = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresURIValidator_Validate(t *testing.T) {
+	v := PostgresURIValidator{
+		Defaults:       uriDefaults,
+		AllowedSchemes: []string{tcpParam, "postgresql", "unix"},
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"+tcp", "tcp://localhost:5432", false},
+		{"+tcpNoPort", "tcp://localhost", false},
+		{"+postgresql", "postgresql://localhost:5433", false},
+		{"+postgresqlNoPort", "postgresql://localhost", false},
+		{"+noScheme", "localhost", false},
+		{"-tcpPortOutOfRange", "tcp://localhost:65536", true},
+		{"-postgresqlPortZero", "postgresql://localhost:0", true},
+		{"+socket", "unix:/var/run/postgresql/.s.PGSQL.5432", false},
+		{"+socketMaxPort", "/var/run/postgresql/.s.PGSQL.65535", false},
+		{"-socketPortOutOfRange", "unix:/var/run/postgresql/.s.PGSQL.99999", true},
+		{"-socketPortZero", "/var/run/postgresql/.s.PGSQL.0", true},
+		{"-socketDirectory", "unix:/var/run/postgresql", true},
+		{"-scheme", "http://localhost", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+
+			err := v.Validate(&value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresURIValidator.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_newURI(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawURI     string
+		wantScheme string
+		wantAddr   string
+		wantErr    bool
+	}{
+		{"+tcp", "tcp://localhost:5433", "tcp", "localhost:5433", false},
+		{"+tcpNoPort", "tcp://localhost", "tcp", "localhost:5432", false},
+		{"+postgresql", "postgresql://db.example.com:6432", "postgresql", "db.example.com:6432", false},
+		{"+postgresqlNoPort", "postgresql://db.example.com", "postgresql", "db.example.com:5432", false},
+		{"+postgresqlNoPortWithDB", "postgresql://db.example.com?dbname=zabbix", "postgresql", "db.example.com:5432", false},
+		{"+noScheme", "localhost", "tcp", "localhost:5432", false},
+		{"+noSchemeWithPort", "localhost:5433", "tcp", "localhost:5433", false},
+		{"+unix", "unix:/var/run/postgresql/.s.PGSQL.5433", "unix", "/var/run/postgresql/.s.PGSQL.5433", false},
+		{"+unixNoScheme", "/var/run/postgresql/.s.PGSQL.5432", "unix", "/var/run/postgresql/.s.PGSQL.5432", false},
+		{"-noHost", "tcp://:5432", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newURI(tt.rawURI, "postgres", "secret", uriDefaults)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newURI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.Scheme() != tt.wantScheme {
+				t.Errorf("newURI() scheme = %q, want %q", got.Scheme(), tt.wantScheme)
+			}
+
+			if got.Addr() != tt.wantAddr {
+				t.Errorf("newURI() address = %q, want %q", got.Addr(), tt.wantAddr)
+			}
+
+			if got.User() != "postgres" || got.Password() != "secret" {
+				t.Errorf("newURI() credentials = %q, %q, want %q, %q", got.User(), got.Password(), "postgres", "secret")
+			}
+		})
+	}
+}
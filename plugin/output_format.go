@@ -0,0 +1,249 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.zabbix.com/sdk/errs"
+)
+
+// Formats of the OutputFormat session option.
+const (
+	outputFormatJSON = "json"
+	outputFormatCSV  = "csv"
+	outputFormatKV   = "kv"
+)
+
+var outputFormats = []string{outputFormatJSON, outputFormatCSV, outputFormatKV}
+
+// discoveryKeys are low-level discovery keys, Zabbix accepts their results as JSON only, so they aren't converted
+// to the output format.
+var discoveryKeys = map[string]bool{
+	keyDatabaseBloatingDiscovery:       true,
+	keyDatabasesDiscovery:              true,
+	keyDatabasesDiscoveryExt:           true,
+	keyIndexesDiscovery:                true,
+	keyPartitionsDiscovery:             true,
+	keyRelationDiscovery:               true,
+	keyReplicationProcessNameDiscovery: true,
+}
+
+// outputField is a scalar value of a JSON document with its dotted path, e.g. "0.pid" for the pid field of the first
+// array element.
+type outputField struct {
+	path  string
+	value string
+}
+
+// serialize formats a handler result with formatResult and converts a JSON document to the output format of
+// the session of the key, scalar values and low-level discovery results are returned unchanged.
+func (p *Plugin) serialize(key string, result any, params map[string]string) (any, error) {
+	doc, err := formatResult(key, result, p.options.SchemaMetaEnabled)
+	if err != nil || !isJSONDocument(result) {
+		return doc, err
+	}
+
+	s, ok := doc.(string)
+	if !ok || discoveryKeys[key] {
+		return doc, nil
+	}
+
+	return convertOutput(s, params[outputFormatParam])
+}
+
+// isJSONDocument returns true for results formatResult serializes to JSON.
+func isJSONDocument(result any) bool {
+	if _, ok := result.(jsonResult); ok {
+		return true
+	}
+
+	return result != nil && isCompositeValue(result)
+}
+
+// convertOutput converts a JSON document to the output format, the JSON format and an empty format return the
+// document unchanged.
+func convertOutput(doc, format string) (string, error) {
+	switch format {
+	case outputFormatCSV:
+		return jsonToCSV(doc)
+	case outputFormatKV:
+		return jsonToKV(doc)
+	default:
+		return doc, nil
+	}
+}
+
+// jsonToCSV converts a JSON document to CSV with a header line. Elements of an array are rows and an object is
+// a single row, nested fields become columns named by their dotted paths. Columns are ordered by their first
+// appearance, a field missing in a row and null are empty cells. An empty array gives an empty result.
+func jsonToCSV(doc string) (string, error) {
+	root, err := decodeJSON(doc)
+	if err != nil {
+		return "", err
+	}
+
+	rows, ok := root.([]any)
+	if !ok {
+		rows = []any{root}
+	}
+
+	var (
+		columns []string
+		seen    = map[string]bool{}
+		records = make([]map[string]string, 0, len(rows))
+	)
+
+	for _, row := range rows {
+		record := map[string]string{}
+
+		for _, f := range flattenJSON("", row, nil) {
+			path := f.path
+			if path == "" {
+				path = "value"
+			}
+
+			if !seen[path] {
+				seen[path] = true
+				columns = append(columns, path)
+			}
+
+			record[path] = f.value
+		}
+
+		records = append(records, record)
+	}
+
+	if len(columns) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+
+	w := csv.NewWriter(&b)
+
+	err = w.Write(columns)
+	if err != nil {
+		return "", errs.Wrap(err, "cannot write CSV")
+	}
+
+	for _, record := range records {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i] = record[column]
+		}
+
+		err = w.Write(line)
+		if err != nil {
+			return "", errs.Wrap(err, "cannot write CSV")
+		}
+	}
+
+	w.Flush()
+
+	err = w.Error()
+	if err != nil {
+		return "", errs.Wrap(err, "cannot write CSV")
+	}
+
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// jsonToKV converts a JSON document to "path=value" lines, one per scalar value, paths are dotted like in
+// jsonToCSV. Values with line breaks are quoted, so every value takes a single line.
+func jsonToKV(doc string) (string, error) {
+	root, err := decodeJSON(doc)
+	if err != nil {
+		return "", err
+	}
+
+	fields := flattenJSON("", root, nil)
+	lines := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		path := f.path
+		if path == "" {
+			path = "value"
+		}
+
+		value := f.value
+		if strings.ContainsAny(value, "\r\n") {
+			value = strconv.Quote(value)
+		}
+
+		lines = append(lines, path+"="+value)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// decodeJSON decodes a JSON document keeping numbers as they are written, e.g. big LSN differences.
+func decodeJSON(doc string) (any, error) {
+	var root any
+
+	d := json.NewDecoder(strings.NewReader(doc))
+	d.UseNumber()
+
+	err := d.Decode(&root)
+	if err != nil {
+		return nil, errs.Wrap(err, "cannot decode JSON result")
+	}
+
+	return root, nil
+}
+
+// flattenJSON appends scalar values of v to fields, object fields are sorted by name and array elements keep their
+// order.
+func flattenJSON(path string, v any, fields []outputField) []outputField {
+	switch val := v.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fields = flattenJSON(joinPath(path, name), val[name], fields)
+		}
+	case []any:
+		for i, elem := range val {
+			fields = flattenJSON(joinPath(path, strconv.Itoa(i)), elem, fields)
+		}
+	case nil:
+		fields = append(fields, outputField{path, ""})
+	case string:
+		fields = append(fields, outputField{path, val})
+	case json.Number:
+		fields = append(fields, outputField{path, val.String()})
+	case bool:
+		fields = append(fields, outputField{path, strconv.FormatBool(val)})
+	}
+
+	return fields
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
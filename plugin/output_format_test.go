@@ -0,0 +1,161 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_convertOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{"+json", `{"b":1,"a":"x"}`, outputFormatJSON, `{"b":1,"a":"x"}`, false},
+		{"+emptyFormat", `{"b":1,"a":"x"}`, "", `{"b":1,"a":"x"}`, false},
+		{"+csvObject", `{"b":1,"a":"x"}`, outputFormatCSV, "a,b\nx,1", false},
+		{
+			"+csvArray",
+			`[{"pid":1,"state":"active"},{"pid":2,"wait":null,"state":"idle, long"}]`,
+			outputFormatCSV,
+			"pid,state,wait\n1,active,\n2,\"idle, long\",",
+			false,
+		},
+		{"+csvNested", `{"a":{"b":[1,2]},"c":true}`, outputFormatCSV, "a.b.0,a.b.1,c\n1,2,true", false},
+		{"+csvScalars", `[1,2]`, outputFormatCSV, "value\n1\n2", false},
+		{"+csvEmptyArray", `[]`, outputFormatCSV, "", false},
+		{"+kvObject", `{"b":1,"a":"x","c":null}`, outputFormatKV, "a=x\nb=1\nc=", false},
+		{"+kvArray", `[{"pid":1},{"pid":2}]`, outputFormatKV, "0.pid=1\n1.pid=2", false},
+		{"+kvLineBreak", `{"query":"SELECT 1\nFROM t"}`, outputFormatKV, `query="SELECT 1\nFROM t"`, false},
+		{"+kvBigNumber", `{"write":18446744073709551615}`, outputFormatKV, "write=18446744073709551615", false},
+		{"-invalidJSON", `{"a":`, outputFormatKV, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertOutput(tt.doc, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("convertOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlugin_serialize_connections(t *testing.T) {
+	const connectionsJSON = `{"active":2,"idle":5,"idle_in_transaction":1,"total":8}`
+
+	tests := []struct {
+		name   string
+		format string
+		want   any
+	}{
+		{"+default", "", connectionsJSON},
+		{"+json", outputFormatJSON, connectionsJSON},
+		{"+csv", outputFormatCSV, "active,idle,idle_in_transaction,total\n2,5,1,8"},
+		{"+kv", outputFormatKV, "active=2\nidle=5\nidle_in_transaction=1\ntotal=8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %s", err.Error())
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM pg_stat_activity`).
+				WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(connectionsJSON))
+
+			result, err := connectionsHandler(context.Background(), &PGConn{client: db}, keyConnections, nil)
+			if err != nil {
+				t.Fatalf("connectionsHandler() error = %v", err)
+			}
+
+			p := &Plugin{}
+
+			got, err := p.serialize(keyConnections, result, map[string]string{outputFormatParam: tt.format})
+			if err != nil {
+				t.Fatalf("Plugin.serialize() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("Plugin.serialize() = %q, want %q", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("connectionsHandler() sql mock expectations where not met: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestPlugin_serialize_discovery(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		result any
+	}{
+		{"+discoveryKey", keyDatabasesDiscovery, jsonResult(`[{"{#DBNAME}":"postgres"}]`)},
+		{"+discoveryExtKey", keyDatabasesDiscoveryExt, jsonResult(`[{"{#DBNAME}":"postgres","{#SIZE}":8192}]`)},
+		{"+relationDiscovery", keyRelationDiscovery, jsonResult(`[{"{#SCHEMA}":"public","{#NAME}":"t"}]`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{}
+
+			got, err := p.serialize(tt.key, tt.result, map[string]string{outputFormatParam: outputFormatCSV})
+			if err != nil {
+				t.Fatalf("Plugin.serialize() error = %v", err)
+			}
+
+			if got != string(tt.result.(jsonResult)) {
+				t.Errorf("Plugin.serialize() = %v, want %v unchanged", got, tt.result)
+			}
+		})
+	}
+}
+
+func Test_discoveryKeys(t *testing.T) {
+	for key := range metrics {
+		if strings.HasSuffix(key, ".discovery") || strings.Contains(key, ".discovery.") {
+			if !discoveryKeys[key] {
+				t.Errorf("discovery key %q is missing in discoveryKeys", key)
+			}
+		}
+	}
+}
+
+func TestPlugin_serialize_scalar(t *testing.T) {
+	p := &Plugin{}
+
+	got, err := p.serialize(keyPing, pingOk, map[string]string{outputFormatParam: outputFormatCSV})
+	if err != nil {
+		t.Fatalf("Plugin.serialize() error = %v", err)
+	}
+
+	if got != pingOk {
+		t.Errorf("Plugin.serialize() = %v, want %v unchanged", got, pingOk)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/omeid/go-yarn"
@@ -54,8 +55,12 @@ var Impl Plugin
 //
 //nolint:gocyclo,cyclop
 func (p *Plugin) Export(key string, rawParams []string, pluginCtx plugin.ContextProvider) (any, error) {
-	if key == keyCustomQuery && !p.options.CustomQueriesEnabled {
-		return nil, errs.Errorf("key %q is disabled", keyCustomQuery)
+	if (key == keyCustomQuery || key == keyCustomQueryMulti) && !p.options.CustomQueriesEnabled {
+		return nil, errs.Errorf("key %q is disabled", key)
+	}
+
+	if key == keyQueriesList && !p.options.QueriesListEnabled {
+		return nil, errs.Errorf("key %q is disabled", key)
 	}
 
 	m, ok := metrics[key]
@@ -63,16 +68,22 @@ func (p *Plugin) Export(key string, rawParams []string, pluginCtx plugin.Context
 		return nil, errs.Wrapf(zbxerr.ErrorUnsupportedMetric, "unknown metric %q", key)
 	}
 
-	params, extraParams, hc, err := m.EvalParams(rawParams, p.options.Sessions)
+	params, extraParams, err := p.evalParams(m, rawParams)
 	if err != nil {
 		return nil, err
 	}
 
-	err = metric.SetDefaults(params, hc, p.options.Default)
-	if err != nil {
-		return nil, err
+	// pgsql.plugin.last_error and pgsql.plugin.connections describe cached connections, so no connection is needed.
+	if key == keyPluginLastError {
+		return p.serialize(key, p.connMgr.lastErrors(), params)
+	}
+
+	if key == keyPluginConnections {
+		return p.serialize(key, p.connMgr.connectionsInfo(), params)
 	}
 
+	setClusterDatabase(key, params, p.options.ClusterDatabase)
+
 	connID, err := createConnID(params)
 	if err != nil {
 		return nil, err
@@ -91,8 +102,23 @@ func (p *Plugin) Export(key string, rawParams []string, pluginCtx plugin.Context
 			return pingFailed, nil
 		}
 
+		// pgsql.ping.detail describes connection errors instead of failing.
+		if key == keyPingDetail {
+			return p.serialize(key, getPingDetail(err), params)
+		}
+
+		// pgsql.health reports a failed server instead of failing.
+		if key == keyHealth {
+			return p.serialize(key, connectionFailedHealth(err), params)
+		}
+
 		p.Errf(err.Error())
 
+		// Any key reports a connection status instead of failing, so items stay supported.
+		if p.options.GracefulConnErrors {
+			return p.serialize(key, getConnStatus(err), params)
+		}
+
 		return nil, err
 	}
 
@@ -102,20 +128,35 @@ func (p *Plugin) Export(key string, rawParams []string, pluginCtx plugin.Context
 		timeout = time.Second * time.Duration(pluginCtx.Timeout())
 	}
 
+	// A custom query may set its own timeout, e.g. a longer one for a heavy query.
+	if key == keyCustomQuery || key == keyCustomQueryMulti {
+		queryTimeout, ok, err := conn.queryTimeoutByName(params["QueryName"])
+		if err != nil {
+			return nil, zbxerr.ErrorInvalidParams.Wrap(err)
+		}
+
+		if ok {
+			timeout = queryTimeout
+		}
+
+		// Limits of custom query results aren't key parameters, they are set from the configuration only.
+		params[maxRowsParam] = strconv.Itoa(p.options.CustomQueriesMaxRows)
+		params[maxColumnsParam] = strconv.Itoa(p.options.CustomQueriesMaxColumns)
+	}
+
 	handlerCtx, cancel := context.WithTimeout(conn.ctx, timeout)
 	defer cancel()
 
 	result, err := handleMetric(handlerCtx, conn, key, params, extraParams...)
+	if err != nil {
+		// A query returning no rows may give a zero or an empty value instead of an error.
+		result, err = handleEmptyResult(err, p.options.OnEmptyResult)
+	}
+
 	if err != nil {
 		ctxErr := handlerCtx.Err()
 		if ctxErr != nil && errors.Is(ctxErr, context.DeadlineExceeded) {
-			p.Errf(
-				"failed to handle metric: query execution timeout %s exceeded: %s",
-				timeout.String(),
-				err.Error(),
-			)
-
-			return nil, errs.New("query execution timeout exceeded")
+			return p.queryTimeoutResult(key, timeout, err)
 		}
 
 		p.Errf("failed to handle metric %q: %s", key, err.Error())
@@ -123,7 +164,75 @@ func (p *Plugin) Export(key string, rawParams []string, pluginCtx plugin.Context
 		return nil, err
 	}
 
-	return result, err
+	return p.serialize(key, result, params)
+}
+
+// queryTimeoutResult returns the result of a key whose query execution timeout is exceeded: TimeoutResult for keys
+// listed in TimeoutResultKeys, so e.g. availability items stay supported, and a generic error for other keys.
+func (p *Plugin) queryTimeoutResult(key string, timeout time.Duration, err error) (any, error) {
+	p.Errf(
+		"failed to handle metric: query execution timeout %s exceeded: %s",
+		timeout.String(),
+		err.Error(),
+	)
+
+	if isTimeoutResultKey(p.options.TimeoutResultKeys, key) {
+		return p.options.TimeoutResult, nil
+	}
+
+	return nil, errs.New("query execution timeout exceeded")
+}
+
+// evalParams evaluates metric parameters and fills in values of the default session.
+func (p *Plugin) evalParams(m *metric.Metric, rawParams []string) (map[string]string, []string, error) {
+	params, extraParams, hc, err := m.EvalParams(rawParams, p.options.Sessions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = metric.SetDefaults(params, hc, p.options.Default)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if params[metric.SessionParam] == "" {
+		inheritDefaultTLS(params, p.options.Default)
+	}
+
+	params[tagsParam] = formatTags(p.sessionTags(params[metric.SessionParam]))
+
+	// CallTimeout of a session isn't a key parameter, it is set from the configuration only.
+	params[callTimeoutParam] = ""
+	if timeout := p.sessionCallTimeout(params[metric.SessionParam]); timeout != 0 {
+		params[callTimeoutParam] = strconv.Itoa(timeout)
+	}
+
+	// ConnectTimeout of a session isn't a key parameter either.
+	params[connectTimeoutParam] = ""
+	if timeout := p.sessionConnectTimeout(params[metric.SessionParam]); timeout != 0 {
+		params[connectTimeoutParam] = strconv.Itoa(timeout)
+	}
+
+	return params, extraParams, nil
+}
+
+// inheritDefaultTLS sets TLS parameters missing in params to values of the default session. TLS parameters are
+// session only, so a connection given by URI, User and Password parameters can get them only from the default
+// session.
+func inheritDefaultTLS(params map[string]string, defaults Session) { //nolint:gocritic
+	defaultTLS := map[string]string{
+		tlsConnectParam:     defaults.TLSConnect,
+		tlsCAParam:          defaults.TLSCAFile,
+		tlsCertParam:        defaults.TLSCertFile,
+		tlsKeyParam:         defaults.TLSKeyFile,
+		tlsKeyPasswordParam: defaults.TLSKeyPassword,
+	}
+
+	for name, value := range defaultTLS {
+		if params[name] == "" {
+			params[name] = value
+		}
+	}
 }
 
 // Start implements the Runner interface and performs initialization when plugin is activated.
@@ -132,9 +241,58 @@ func (p *Plugin) Start() {
 		time.Duration(p.options.KeepAlive)*time.Second,
 		time.Duration(p.options.Timeout)*time.Second,
 		time.Duration(p.options.CallTimeout)*time.Second,
+		time.Duration(p.options.LockTimeout)*time.Second,
+		time.Duration(p.options.DNSCacheTTL)*time.Second,
 		hkInterval*time.Second,
 		p.setCustomQuery(),
+		p.options.ConnectionCheckEnabled,
+		p.options.MaxConnections,
 	)
+
+	if p.options.PrewarmSessions {
+		go p.prewarmSessions(p.connMgr.ctx, p.connMgr)
+	}
+}
+
+// prewarmSessions opens connections of named sessions, so the first checks don't pay for connecting. A session
+// which fails to connect is only logged and is connected again on its first check. Prewarming stops when ctx is
+// done, i.e. the connection manager is destroyed, and a connection opened after that is closed.
+func (p *Plugin) prewarmSessions(ctx context.Context, connMgr *ConnManager) {
+	for name := range p.options.Sessions {
+		if ctx.Err() != nil {
+			return
+		}
+
+		params, _, err := p.evalParams(metrics[keyPing], []string{name})
+		if err != nil {
+			p.Warningf("cannot prewarm session %q: %s", name, err.Error())
+
+			continue
+		}
+
+		ci, err := createConnID(params)
+		if err != nil {
+			p.Warningf("cannot prewarm session %q: %s", name, err.Error())
+
+			continue
+		}
+
+		conn, err := connMgr.GetConnection(ci, params)
+		if err != nil {
+			p.Warningf("cannot prewarm session %q: %s", name, err.Error())
+
+			continue
+		}
+
+		// The manager may have closed its connections while this one was being opened.
+		if ctx.Err() != nil {
+			connMgr.evict(ci, conn)
+
+			return
+		}
+
+		p.Debugf("prewarmed connection of session %q", name)
+	}
 }
 
 func (p *Plugin) setCustomQuery() yarn.Yarn {
@@ -21,6 +21,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/omeid/go-yarn"
 	"golang.zabbix.com/sdk/log"
@@ -62,6 +63,42 @@ func TestPlugin_Start(t *testing.T) {
 	})
 }
 
+func TestPlugin_Start_prewarmSessions(t *testing.T) {
+	pgAddr, pgUser, pgPwd, pgDb := getEnv()
+
+	p := &Plugin{}
+	p.Init(Name)
+	p.options = PluginOptions{
+		Timeout:              plugin.DefaultPluginTimeout,
+		CallTimeout:          plugin.DefaultPluginTimeout,
+		KeepAlive:            300,
+		CustomQueriesMaxRows: 10000,
+		PrewarmSessions:      true,
+		Sessions: map[string]Session{
+			"Prewarm": {URI: "tcp://" + pgAddr, User: pgUser, Password: pgPwd, Database: pgDb},
+		},
+	}
+
+	p.Start()
+	defer p.Stop()
+
+	connections := func() int {
+		p.connMgr.connectionsMu.Lock()
+		defer p.connMgr.connectionsMu.Unlock()
+
+		return len(p.connMgr.connections)
+	}
+
+	deadline := time.Now().Add(time.Duration(plugin.DefaultPluginTimeout) * time.Second)
+	for connections() == 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if got := connections(); got != 1 {
+		t.Fatalf("Plugin.Start() prewarmed %d connections, want 1", got)
+	}
+}
+
 func TestPlugin_Export(t *testing.T) {
 	pgAddr, pgUser, pgPwd, pgDb := getEnv()
 
@@ -0,0 +1,171 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/errs"
+)
+
+// Modes of the OnEmptyResult option.
+const (
+	onEmptyResultError = "error"
+	onEmptyResultZero  = "zero"
+	onEmptyResultEmpty = "empty"
+)
+
+// Codes of a connection status returned instead of connection errors if GracefulConnErrors is enabled.
+const (
+	connStatusUnreachable     = 1
+	connStatusAuthFailed      = 2
+	connStatusDatabaseMissing = 3
+	connStatusRejected        = 4
+)
+
+// connStatus is a result of a key whose connection failed if GracefulConnErrors is enabled.
+type connStatus struct {
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
+
+// jsonResult is a JSON document built by the server, e.g. with json_agg or row_to_json. Handlers return it instead
+// of a plain string, so formatResult can tell JSON documents from scalar values.
+type jsonResult string
+
+// formatResult serializes a handler result into the value returned to the agent. It is the only place results are
+// turned into strings: JSON documents built by the server are validated and returned as is, maps, slices and
+// structs are marshaled to JSON and scalar values are returned unchanged. If schemaMeta is set the schema meta
+// field is added to JSON objects of schema versioned keys.
+func formatResult(key string, result any, schemaMeta bool) (any, error) {
+	var doc string
+
+	switch v := result.(type) {
+	case nil:
+		return nil, nil //nolint:nilnil
+	case jsonResult:
+		if !json.Valid([]byte(v)) {
+			return nil, errs.Errorf("result of %q is not valid JSON", key)
+		}
+
+		doc = string(v)
+	default:
+		if !isCompositeValue(v) {
+			return result, nil
+		}
+
+		res, err := json.Marshal(v)
+		if err != nil {
+			return nil, errs.Wrapf(err, "cannot marshal result of %q", key)
+		}
+
+		doc = string(res)
+	}
+
+	if schemaMeta {
+		return addSchemaMeta(key, doc)
+	}
+
+	return doc, nil
+}
+
+// isCompositeValue returns true for values serialized as JSON objects or arrays.
+func isCompositeValue(v any) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer:
+		return true
+	default:
+		return false
+	}
+}
+
+// getConnStatus finds out the code of a connection error by the step the connection failed at.
+func getConnStatus(err error) connStatus {
+	detail := getPingDetail(err)
+
+	status := connStatusRejected
+
+	switch {
+	case !detail.Reachable:
+		status = connStatusUnreachable
+	case detail.Authenticated != nil && !*detail.Authenticated:
+		status = connStatusAuthFailed
+	case detail.DatabaseExists != nil && !*detail.DatabaseExists:
+		status = connStatusDatabaseMissing
+	}
+
+	return connStatus{Status: status, Error: err.Error()}
+}
+
+// handleEmptyResult returns the value of a key whose query returned no rows according to the OnEmptyResult mode:
+// 0 for the zero mode and an empty string for the empty mode. Other errors and the error mode return err unchanged.
+func handleEmptyResult(err error, mode string) (any, error) {
+	if !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	switch mode {
+	case onEmptyResultZero:
+		return 0, nil
+	case onEmptyResultEmpty:
+		return "", nil
+	default:
+		return nil, err
+	}
+}
+
+// isTimeoutResultKey reports whether a key is listed in a comma separated list of the TimeoutResultKeys option.
+func isTimeoutResultKey(keys, key string) bool {
+	for _, k := range strings.Split(keys, ",") {
+		if strings.TrimSpace(k) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateTimeoutResultKeys checks that keys of the TimeoutResultKeys option are known, empty list means no keys.
+func validateTimeoutResultKeys(keys string) error {
+	if strings.TrimSpace(keys) == "" {
+		return nil
+	}
+
+	for _, k := range strings.Split(keys, ",") {
+		if _, ok := metrics[strings.TrimSpace(k)]; !ok {
+			return errs.Errorf("TimeoutResultKeys contains unknown key %q", strings.TrimSpace(k))
+		}
+	}
+
+	return nil
+}
+
+// validateOnEmptyResult checks a mode of the OnEmptyResult option, empty mode means the default error mode.
+func validateOnEmptyResult(mode string) error {
+	switch mode {
+	case "", onEmptyResultError, onEmptyResultZero, onEmptyResultEmpty:
+		return nil
+	default:
+		return errs.Errorf(
+			"OnEmptyResult %q must be one of %s, %s or %s",
+			mode, onEmptyResultError, onEmptyResultZero, onEmptyResultEmpty,
+		)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"golang.zabbix.com/sdk/zbxerr"
+)
+
+func Test_formatResult(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		key        string
+		result     any
+		schemaMeta bool
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    any
+		wantErr bool
+	}{
+		{
+			"+jsonResult",
+			args{keyLocks, jsonResult(`{"postgres" : {"total":1}}`), false},
+			`{"postgres" : {"total":1}}`,
+			false,
+		},
+		{
+			"+struct",
+			args{
+				keyVersionParsed,
+				parsedVersion{Major: 17, Minor: 2, Full: "PostgreSQL 17.2", ServerVersionNum: 170002},
+				false,
+			},
+			`{"major":17,"minor":2,"full":"PostgreSQL 17.2","server_version_num":170002}`,
+			false,
+		},
+		{
+			"+map",
+			args{keyQueriesList, map[string][]string{keyUptime: {"SELECT 1;"}}, false},
+			`{"pgsql.uptime":["SELECT 1;"]}`,
+			false,
+		},
+		{
+			"+schemaMeta",
+			args{keyConnections, jsonResult(`{"active":1}`), true},
+			`{"_meta":{"schema_version":1},"active":1}`,
+			false,
+		},
+		{
+			"+schemaMetaDisabled",
+			args{keyConnections, jsonResult(`{"active":1}`), false},
+			`{"active":1}`,
+			false,
+		},
+		{"+int", args{keyUptime, int64(42), true}, int64(42), false},
+		{"+float", args{keyCache, 99.5, false}, 99.5, false},
+		{"+string", args{keyVersion, "PostgreSQL 17.2", true}, "PostgreSQL 17.2", false},
+		{"+nil", args{keyVersion, nil, false}, nil, false},
+		{"-invalidJSON", args{keyLocks, jsonResult(`{"postgres":`), false}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := formatResult(tt.args.key, tt.args.result, tt.args.schemaMeta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("formatResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("formatResult() = %s", diff)
+			}
+		})
+	}
+}
+
+func Test_handleEmptyResult(t *testing.T) {
+	t.Parallel()
+
+	noRows := zbxerr.ErrorEmptyResult.Wrap(pgx.ErrNoRows)
+	sqlNoRows := zbxerr.ErrorCannotFetchData.Wrap(sql.ErrNoRows)
+	queryErr := zbxerr.ErrorCannotFetchData.Wrap(errors.New("query err"))
+
+	tests := []struct {
+		name    string
+		err     error
+		mode    string
+		want    any
+		wantErr error
+	}{
+		{"+errorMode", noRows, onEmptyResultError, nil, noRows},
+		{"+defaultMode", noRows, "", nil, noRows},
+		{"+zeroMode", noRows, onEmptyResultZero, 0, nil},
+		{"+emptyMode", noRows, onEmptyResultEmpty, "", nil},
+		{"+zeroModeSQLNoRows", sqlNoRows, onEmptyResultZero, 0, nil},
+		{"+emptyModeSQLNoRows", sqlNoRows, onEmptyResultEmpty, "", nil},
+		{"+errorModeSQLNoRows", sqlNoRows, onEmptyResultError, nil, sqlNoRows},
+		{"-zeroModeOtherErr", queryErr, onEmptyResultZero, nil, queryErr},
+		{"-emptyModeOtherErr", queryErr, onEmptyResultEmpty, nil, queryErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := handleEmptyResult(tt.err, tt.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("handleEmptyResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("handleEmptyResult() = %s", diff)
+			}
+		})
+	}
+}
+
+func Test_getConnStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want connStatus
+	}{
+		{
+			"+unreachable",
+			errors.New("connection refused"),
+			connStatus{Status: connStatusUnreachable, Error: "connection refused"},
+		},
+		{
+			"+authFailed",
+			&pgconn.PgError{Severity: "FATAL", Code: sqlStateInvalidPassword, Message: "auth failed"},
+			connStatus{Status: connStatusAuthFailed, Error: "FATAL: auth failed (SQLSTATE 28P01)"},
+		},
+		{
+			"+databaseMissing",
+			&pgconn.PgError{Severity: "FATAL", Code: sqlStateInvalidCatalogName, Message: "no database"},
+			connStatus{Status: connStatusDatabaseMissing, Error: "FATAL: no database (SQLSTATE 3D000)"},
+		},
+		{
+			"+rejected",
+			&pgconn.PgError{Severity: "FATAL", Code: "53300", Message: "too many clients"},
+			connStatus{Status: connStatusRejected, Error: "FATAL: too many clients (SQLSTATE 53300)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, getConnStatus(tt.err)); diff != "" {
+				t.Fatalf("getConnStatus() = %s", diff)
+			}
+		})
+	}
+}
+
+func TestPlugin_queryTimeoutResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    string
+		want    any
+		wantErr bool
+	}{
+		{"+timeoutResultKey", "pgsql.ping, pgsql.connections.available", 2, false},
+		{"-notListed", "pgsql.ping", nil, true},
+		{"-noKeys", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sql mock: %v", err)
+			}
+
+			defer db.Close()
+
+			mock.ExpectQuery(`FROM pg_catalog.pg_settings`).
+				WillDelayFor(time.Second).
+				WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(10))
+
+			rawParams := []string{"tcp://localhost"}
+			options := PluginOptions{TimeoutResultKeys: tt.keys, TimeoutResult: 2}
+			p, ci := newCachedConnPlugin(t, db, options, keyConnectionsAvailable, rawParams)
+
+			got, err := p.Export(keyConnectionsAvailable, rawParams, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Plugin.Export() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("Plugin.Export() = %s", diff)
+			}
+
+			if _, ok := p.connMgr.connections[ci]; !ok {
+				t.Fatal("Plugin.Export() evicted the connection after a query timeout")
+			}
+		})
+	}
+}
+
+func Test_validateTimeoutResultKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		keys    string
+		wantErr bool
+	}{
+		{"+empty", "", false},
+		{"+single", "pgsql.ping", false},
+		{"+several", "pgsql.ping, pgsql.health", false},
+		{"-unknown", "pgsql.ping,pgsql.unknown", true},
+		{"-emptyItem", "pgsql.ping,", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateTimeoutResultKeys(tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateTimeoutResultKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateOnEmptyResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr bool
+	}{
+		{"+empty", "", false},
+		{"+error", onEmptyResultError, false},
+		{"+zero", onEmptyResultZero, false},
+		{"+emptyValue", onEmptyResultEmpty, false},
+		{"-unknown", "null", true},
+		{"-caseSensitive", "Zero", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateOnEmptyResult(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateOnEmptyResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
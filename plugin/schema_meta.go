@@ -0,0 +1,72 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.zabbix.com/sdk/errs"
+)
+
+// metricSchemaVersion is a version of JSON structure returned by schema versioned keys. It must be incremented
+// each time a field is removed or renamed or its type is changed, adding a new field doesn't change the version.
+const metricSchemaVersion = 1
+
+const schemaMetaField = "_meta"
+
+// schemaVersionedKeys are keys returning JSON objects that get the schema meta field.
+var schemaVersionedKeys = map[string]bool{
+	keyArchiveSize: true,
+	keyBgwriter:    true,
+	keyConnections: true,
+	keyDBStat:      true,
+	keyDBStatSum:   true,
+	keyWal:         true,
+}
+
+type schemaMeta struct {
+	SchemaVersion int `json:"schema_version"`
+}
+
+// addSchemaMeta adds the schema meta field to a JSON object returned for a schema versioned key,
+// results of other keys are returned as is.
+func addSchemaMeta(key string, result any) (any, error) {
+	if !schemaVersionedKeys[key] {
+		return result, nil
+	}
+
+	obj, ok := result.(string)
+	if !ok {
+		return result, nil
+	}
+
+	obj = strings.TrimSpace(obj)
+	if !strings.HasPrefix(obj, "{") || !json.Valid([]byte(obj)) {
+		return nil, errs.Errorf("cannot add schema meta, result of %q is not a JSON object", key)
+	}
+
+	meta, err := json.Marshal(schemaMeta{SchemaVersion: metricSchemaVersion})
+	if err != nil {
+		return nil, errs.Wrap(err, "cannot marshal schema meta")
+	}
+
+	rest := strings.TrimSpace(obj[1:])
+	if rest != "}" {
+		rest = "," + rest
+	}
+
+	return `{"` + schemaMetaField + `":` + string(meta) + rest, nil
+}
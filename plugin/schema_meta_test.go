@@ -0,0 +1,97 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_addSchemaMeta(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		result  any
+		want    any
+		wantErr bool
+	}{
+		{
+			"+object",
+			keyConnections,
+			`{"active":1,"idle":2}`,
+			`{"_meta":{"schema_version":1},"active":1,"idle":2}`,
+			false,
+		},
+		{
+			"+spaces",
+			keyBgwriter,
+			" { \"buffers_alloc\":1 } ",
+			`{"_meta":{"schema_version":1},"buffers_alloc":1 }`,
+			false,
+		},
+		{
+			"+emptyObject",
+			keyDBStat,
+			`{}`,
+			`{"_meta":{"schema_version":1}}`,
+			false,
+		},
+		{
+			"+notVersionedKey",
+			keyLocks,
+			`{"postgres":{}}`,
+			`{"postgres":{}}`,
+			false,
+		},
+		{
+			"+notString",
+			keyWal,
+			int64(1),
+			int64(1),
+			false,
+		},
+		{
+			"-array",
+			keyArchiveSize,
+			`[1,2]`,
+			nil,
+			true,
+		},
+		{
+			"-invalidJSON",
+			keyArchiveSize,
+			`{"archived_count":`,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := addSchemaMeta(tt.key, tt.result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("addSchemaMeta() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("addSchemaMeta() = %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.zabbix.com/sdk/errs"
+)
+
+// sqlDir is a directory with SQL files of handlers. A file is named "<name>.sql" for the query used with
+// all server versions, or "<name>.v<major>.sql" for the query used since the major server version.
+//
+// Only queries with variants for different server versions are kept here. A query which is the same for all
+// supported versions stays a constant next to its handler, even if the handler requires a minimum version (e.g.
+// pgVersionWithStatSLRU), as there is nothing to select.
+const sqlDir = "sql"
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// sqlQueries holds variants of embedded queries by name, sorted by version from newest to oldest.
+var sqlQueries = mustLoadQueries(sqlFiles)
+
+// versionedQuery is a query variant used since the server version.
+type versionedQuery struct {
+	version int
+	query   string
+}
+
+// resolveQuery returns the variant of the named query for a server version, the newest variant which doesn't
+// require a newer server is used. Returns an empty string if there are no suitable variants.
+func resolveQuery(name string, version int) string {
+	for _, q := range sqlQueries[name] {
+		if q.version <= version {
+			return q.query
+		}
+	}
+
+	return ""
+}
+
+// mustLoadQueries loads embedded queries, they are part of the binary, so any error is a programming error.
+func mustLoadQueries(fsys fs.FS) map[string][]versionedQuery {
+	queries, err := loadQueries(fsys)
+	if err != nil {
+		panic(err)
+	}
+
+	return queries
+}
+
+// loadQueries reads all SQL files of sqlDir and groups them by query name.
+func loadQueries(fsys fs.FS) (map[string][]versionedQuery, error) {
+	entries, err := fs.ReadDir(fsys, sqlDir)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to read SQL directory")
+	}
+
+	queries := make(map[string][]versionedQuery)
+
+	for _, entry := range entries {
+		name, version, err := parseSQLFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(sqlDir, entry.Name()))
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to read SQL file %q", entry.Name())
+		}
+
+		queries[name] = append(queries[name], versionedQuery{version, strings.TrimSpace(string(data))})
+	}
+
+	for name, variants := range queries {
+		sort.Slice(variants, func(i, j int) bool { return variants[i].version > variants[j].version })
+
+		for i := 1; i < len(variants); i++ {
+			if variants[i].version == variants[i-1].version {
+				return nil, errs.Errorf("duplicate SQL file for query %q and version %d", name, variants[i].version)
+			}
+		}
+	}
+
+	return queries, nil
+}
+
+// parseSQLFileName returns a query name and the server version number a SQL file is used since.
+func parseSQLFileName(fileName string) (string, int, error) {
+	name, ok := strings.CutSuffix(fileName, sqlExt)
+	if !ok || name == "" {
+		return "", 0, errs.Errorf("invalid SQL file name %q", fileName)
+	}
+
+	i := strings.LastIndex(name, ".v")
+	if i < 0 {
+		return name, 0, nil
+	}
+
+	major, err := strconv.Atoi(name[i+2:])
+	if err != nil || major <= 0 || i == 0 {
+		return "", 0, errs.Errorf("invalid version in SQL file name %q", fileName)
+	}
+
+	// Since PostgreSQL 10 server_version_num is major * 10000 + minor.
+	return name[:i], major * 10000, nil
+}
@@ -0,0 +1,24 @@
+SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.datname), '[]')
+  FROM  (
+    SELECT
+      datname
+    , numbackends as numbackends
+    , xact_commit as xact_commit
+    , xact_rollback as xact_rollback
+    , blks_read as blks_read
+    , blks_hit as blks_hit
+    , tup_returned as tup_returned
+    , tup_fetched as tup_fetched
+    , tup_inserted as tup_inserted
+    , tup_updated as tup_updated
+    , tup_deleted as tup_deleted
+    , conflicts as conflicts
+    , temp_files as temp_files
+    , temp_bytes as temp_bytes
+    , deadlocks as deadlocks
+    , COALESCE(checksum_failures, 0) as checksum_failures
+    , blk_read_time as blk_read_time
+    , blk_write_time as blk_write_time
+    FROM pg_catalog.pg_stat_database
+    WHERE datname IS NOT NULL
+  ) T ;
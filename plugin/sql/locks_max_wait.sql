@@ -0,0 +1,11 @@
+SELECT json_build_object(
+    'max_wait', coalesce(max(T.wait), 0)
+  , 'waiters', count(*)
+  , 'waiters_over_threshold', count(*) FILTER (WHERE T.wait > $1)
+  )
+  FROM  (
+    SELECT extract(epoch FROM clock_timestamp() - a.query_start)::bigint AS wait
+    FROM pg_catalog.pg_locks l
+    JOIN pg_catalog.pg_stat_activity a ON a.pid = l.pid
+    WHERE NOT l.granted
+  ) T ;
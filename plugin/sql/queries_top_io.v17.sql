@@ -0,0 +1,17 @@
+SELECT coalesce(json_agg(row_to_json(T) ORDER BY T.io_time DESC), '[]')
+  FROM  (
+    SELECT
+      d.datname
+    , s.queryid
+    , s.query
+    , s.calls
+    , s.shared_blk_read_time + s.local_blk_read_time AS blk_read_time
+    , s.shared_blk_write_time + s.local_blk_write_time AS blk_write_time
+    , s.shared_blk_read_time + s.local_blk_read_time
+      + s.shared_blk_write_time + s.local_blk_write_time AS io_time
+    FROM pg_stat_statements s
+    LEFT JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+    WHERE s.shared_blk_read_time + s.local_blk_read_time + s.shared_blk_write_time + s.local_blk_write_time > 0
+    ORDER BY io_time DESC
+    LIMIT $1
+  ) T ;
@@ -0,0 +1,13 @@
+SELECT coalesce(json_object_agg(T.datname, row_to_json(T)), '{}')
+  FROM  (
+    SELECT
+      d.datname
+    , d.conflicts
+    , c.confl_tablespace
+    , c.confl_lock
+    , c.confl_snapshot
+    , c.confl_bufferpin
+    , c.confl_deadlock
+    FROM pg_catalog.pg_stat_database d
+    JOIN pg_catalog.pg_stat_database_conflicts c ON c.datid = d.datid
+  ) T ;
@@ -0,0 +1,11 @@
+SELECT json_build_object(
+    'current', T.xmax
+  , 'xmin', T.xmin
+  , 'xmax', T.xmax
+  , 'xmin_age', T.xmax - T.xmin
+  )
+  FROM  (
+    SELECT txid_snapshot_xmin(S.snapshot) AS xmin
+      , txid_snapshot_xmax(S.snapshot) AS xmax
+      FROM (SELECT txid_current_snapshot() AS snapshot) S
+  ) T ;
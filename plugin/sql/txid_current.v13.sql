@@ -0,0 +1,11 @@
+SELECT json_build_object(
+    'current', T.xmax
+  , 'xmin', T.xmin
+  , 'xmax', T.xmax
+  , 'xmin_age', T.xmax - T.xmin
+  )
+  FROM  (
+    SELECT pg_snapshot_xmin(S.snapshot)::text::bigint AS xmin
+      , pg_snapshot_xmax(S.snapshot)::text::bigint AS xmax
+      FROM (SELECT pg_current_snapshot() AS snapshot) S
+  ) T ;
@@ -0,0 +1,153 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_resolveQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    string
+		version  int
+		contains string
+		excludes string
+	}{
+		{"+bgwriterV96", "bgwriter", 90600, "buffers_backend_fsync", "pg_stat_checkpointer"},
+		{"+bgwriterV10", "bgwriter", 100000, "buffers_backend_fsync", "pg_stat_checkpointer"},
+		{"+bgwriterV16", "bgwriter", 169999, "buffers_backend_fsync", "pg_stat_checkpointer"},
+		{"+bgwriterV17", "bgwriter", 170000, "pg_stat_checkpointer", "buffers_backend_fsync"},
+		{"+bgwriterV18", "bgwriter", 180000, "pg_stat_checkpointer", "buffers_backend_fsync"},
+		{"+dbstatV11", "dbstat", 119999, "null as checksum_failures", "COALESCE(checksum_failures"},
+		{"+dbstatV12", "dbstat", 120000, "COALESCE(checksum_failures, 0) as", "null as checksum_failures"},
+		{"+dbstatByDBV11", "dbstat_by_db", 119999, "null as checksum_failures", "COALESCE(checksum_failures"},
+		{"+dbstatByDBV12", "dbstat_by_db", 120000, "COALESCE(checksum_failures, 0) as", "null as checksum_failures"},
+		{"+dbstatSumV11", "dbstat_sum", 119999, "null as checksum_failures", "COALESCE(checksum_failures"},
+		{"+dbstatSumV12", "dbstat_sum", 120000, "sum(COALESCE(checksum_failures, 0))", "null as checksum_failures"},
+		{"+locksMaxWaitV13", "locks_max_wait", 139999, "a.query_start)", "waitstart"},
+		{"+locksMaxWaitV14", "locks_max_wait", 140000, "coalesce(l.waitstart, a.query_start)", "- a.query_start)"},
+		{"+queriesTopIOV16", "queries_top_io", 169999, "s.blk_read_time", "shared_blk_read_time"},
+		{"+queriesTopIOV17", "queries_top_io", 170000, "shared_blk_read_time", "s.blk_read_time"},
+		{"+queryCancelV13", "query_cancel", 139999, "confl_deadlock", "sessions_killed"},
+		{"+queryCancelV14", "query_cancel", 140000, "sessions_killed", "confl_active_logicalslot"},
+		{"+queryCancelV16", "query_cancel", 160000, "confl_active_logicalslot", "confl_active_logicalslot_"},
+		{"+txidCurrentV12", "txid_current", 129999, "txid_current_snapshot()", "pg_current_snapshot"},
+		{"+txidCurrentV13", "txid_current", 130000, "pg_current_snapshot()", "txid_current_snapshot()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := resolveQuery(tt.query, tt.version)
+
+			if !strings.Contains(got, tt.contains) {
+				t.Fatalf("resolveQuery(%q, %d) = %q, want to contain %q", tt.query, tt.version, got, tt.contains)
+			}
+
+			if strings.Contains(got, tt.excludes) {
+				t.Fatalf("resolveQuery(%q, %d) = %q, want not to contain %q", tt.query, tt.version, got, tt.excludes)
+			}
+		})
+	}
+
+	if got := resolveQuery("unknown", 170000); got != "" {
+		t.Fatalf("resolveQuery() = %q for unknown query, want empty", got)
+	}
+}
+
+// Every built-in query must have a variant for the oldest supported server, otherwise its key runs an empty query.
+func Test_resolveQuery_minSupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	for name := range sqlQueries {
+		if resolveQuery(name, MinSupportedPGVersion) == "" {
+			t.Errorf("resolveQuery(%q, %d) is empty, want a query", name, MinSupportedPGVersion)
+		}
+	}
+}
+
+func Test_sqlQueries_versioned(t *testing.T) {
+	t.Parallel()
+
+	for name, variants := range sqlQueries {
+		if len(variants) < 2 {
+			t.Errorf("query %q has a single variant, want it to be a constant of its handler", name)
+		}
+	}
+}
+
+func Test_loadQueries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    map[string][]versionedQuery
+		wantErr bool
+	}{
+		{
+			"+versions",
+			fstest.MapFS{
+				"sql/stat.sql":     {Data: []byte("SELECT 1;\n")},
+				"sql/stat.v13.sql": {Data: []byte("SELECT 13;\n")},
+				"sql/stat.v9.sql":  {Data: []byte("SELECT 9;")},
+				"sql/uptime.sql":   {Data: []byte(" SELECT 2; ")},
+			},
+			map[string][]versionedQuery{
+				"stat":   {{130000, "SELECT 13;"}, {90000, "SELECT 9;"}, {0, "SELECT 1;"}},
+				"uptime": {{0, "SELECT 2;"}},
+			},
+			false,
+		},
+		{
+			"+versionOnly",
+			fstest.MapFS{"sql/stat.v13.sql": {Data: []byte("SELECT 13;")}},
+			map[string][]versionedQuery{"stat": {{130000, "SELECT 13;"}}},
+			false,
+		},
+		{"-noDir", fstest.MapFS{}, nil, true},
+		{"-ext", fstest.MapFS{"sql/stat.txt": {}}, nil, true},
+		{"-emptyName", fstest.MapFS{"sql/.sql": {}}, nil, true},
+		{"-badVersion", fstest.MapFS{"sql/stat.vX.sql": {}}, nil, true},
+		{"-zeroVersion", fstest.MapFS{"sql/stat.v0.sql": {}}, nil, true},
+		{"-versionWithoutName", fstest.MapFS{"sql/.v13.sql": {}}, nil, true},
+		{
+			"-duplicate",
+			fstest.MapFS{"sql/stat.v13.sql": {}, "sql/stat.v013.sql": {}},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := loadQueries(tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadQueries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("loadQueries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"sort"
+	"strings"
+
+	"golang.zabbix.com/sdk/errs"
+)
+
+const (
+	// tagsParam holds tags of the session of a key in the form "key=value,key2=value2", it isn't a key parameter
+	// and is set from the configuration only.
+	tagsParam = "Tags"
+
+	// applicationNamePrefix starts application_name of connections with tags, e.g.
+	// "zabbix_agent2 env=prod,role=primary".
+	applicationNamePrefix = "zabbix_agent2"
+)
+
+// validateTags checks that names and values of tags can be joined into a single string and split back.
+func validateTags(tags map[string]string) error {
+	for name, value := range tags {
+		if name == "" {
+			return errs.New("tag name must not be empty")
+		}
+
+		if strings.ContainsAny(name, ",=") || strings.ContainsAny(value, ",=") {
+			return errs.Errorf("tag %q must not contain ',' or '='", name)
+		}
+	}
+
+	return nil
+}
+
+// formatTags joins tags sorted by names into "key=value,key2=value2", empty string is returned for no tags.
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for name, value := range tags {
+		pairs = append(pairs, name+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// parseTags splits tags joined by formatTags.
+func parseTags(tags string) map[string]string {
+	parsed := make(map[string]string)
+
+	if tags == "" {
+		return parsed
+	}
+
+	for _, pair := range strings.Split(tags, ",") {
+		name, value, _ := strings.Cut(pair, "=")
+		parsed[name] = value
+	}
+
+	return parsed
+}
+
+// applicationName returns application_name of connections with tags, empty string is returned for no tags.
+func applicationName(tags string) string {
+	if tags == "" {
+		return ""
+	}
+
+	return applicationNamePrefix + " " + tags
+}
+
+// sessionTags returns tags of a named session, tags of the default session are returned if name is empty or the
+// session has no tags.
+func (p *Plugin) sessionTags(name string) map[string]string {
+	if tags := p.options.Sessions[name].Tags; name != "" && len(tags) > 0 {
+		return tags
+	}
+
+	return p.options.Default.Tags
+}
@@ -0,0 +1,157 @@
+/*
+** Copyright (C) 2001-2025 Zabbix SIA
+**
+** This program is free software: you can redistribute it and/or modify it under the terms of
+** the GNU Affero General Public License as published by the Free Software Foundation, version 3.
+**
+** This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+** without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+** See the GNU Affero General Public License for more details.
+**
+** You should have received a copy of the GNU Affero General Public License along with this program.
+** If not, see <https://www.gnu.org/licenses/>.
+**/
+
+package plugin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_validateTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    map[string]string
+		wantErr bool
+	}{
+		{"+noTags", nil, false},
+		{"+tags", map[string]string{"env": "prod", "role": "primary"}, false},
+		{"+emptyValue", map[string]string{"env": ""}, false},
+		{"-emptyName", map[string]string{"": "prod"}, true},
+		{"-commaInValue", map[string]string{"env": "prod,test"}, true},
+		{"-equalsInName", map[string]string{"env=x": "prod"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTags(tt.tags); (err != nil) != tt.wantErr {
+				t.Errorf("validateTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_formatTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags map[string]string
+		want string
+	}{
+		{"+noTags", nil, ""},
+		{"+single", map[string]string{"env": "prod"}, "env=prod"},
+		{"+sorted", map[string]string{"role": "primary", "env": "prod", "dc": ""}, "dc=,env=prod,role=primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatTags(tt.tags)
+			if got != tt.want {
+				t.Fatalf("formatTags() = %q, want %q", got, tt.want)
+			}
+
+			want := tt.tags
+			if want == nil {
+				want = map[string]string{}
+			}
+
+			if parsed := parseTags(got); !reflect.DeepEqual(parsed, want) {
+				t.Errorf("parseTags() = %v, want %v", parsed, want)
+			}
+		})
+	}
+}
+
+func TestPlugin_evalParams_tags(t *testing.T) {
+	p := &Plugin{options: PluginOptions{
+		Default: Session{Tags: map[string]string{"env": "test"}},
+		Sessions: map[string]Session{
+			"prod":   {URI: "tcp://prod:5432", Tags: map[string]string{"env": "prod", "role": "primary"}},
+			"noTags": {URI: "tcp://other:5432"},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		rawParams []string
+		want      string
+	}{
+		{"+default", []string{"tcp://localhost:5432"}, "env=test"},
+		{"+namedSession", []string{"prod"}, "env=prod,role=primary"},
+		{"+sessionWithoutTags", []string{"noTags"}, "env=test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _, err := p.evalParams(metrics[keyPing], tt.rawParams)
+			if err != nil {
+				t.Fatalf("Plugin.evalParams() error = %v", err)
+			}
+
+			if params[tagsParam] != tt.want {
+				t.Fatalf("Plugin.evalParams() tags = %q, want %q", params[tagsParam], tt.want)
+			}
+
+			ci, err := createConnID(params)
+			if err != nil {
+				t.Fatalf("createConnID() error = %v", err)
+			}
+
+			if ci.tags != tt.want {
+				t.Errorf("createConnID() tags = %q, want %q", ci.tags, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnManager_connectionsInfo(t *testing.T) {
+	lastAccess := time.Unix(1700000000, 0)
+
+	prod, err := newURI("tcp://prod:5432?dbname=postgres", "zabbix", "", uriDefaults)
+	if err != nil {
+		t.Fatalf("newURI() error = %v", err)
+	}
+
+	test, err := newURI("tcp://test:5432?dbname=app", "zabbix", "", uriDefaults)
+	if err != nil {
+		t.Fatalf("newURI() error = %v", err)
+	}
+
+	c := &ConnManager{connections: map[connID]*PGConn{
+		{uri: *test}: {address: "test:5432", version: 150000, lastTimeAccess: lastAccess},
+		{uri: *prod, tags: "env=prod,role=primary"}: {
+			address: "prod:5432", version: 160000, lastTimeAccess: lastAccess,
+		},
+	}}
+
+	want := []connectionInfo{
+		{
+			Address:    "prod:5432",
+			User:       "zabbix",
+			Database:   "postgres",
+			Tags:       map[string]string{"env": "prod", "role": "primary"},
+			Version:    160000,
+			LastAccess: lastAccess.Unix(),
+		},
+		{
+			Address:    "test:5432",
+			User:       "zabbix",
+			Database:   "app",
+			Tags:       map[string]string{},
+			Version:    150000,
+			LastAccess: lastAccess.Unix(),
+		},
+	}
+
+	if got := c.connectionsInfo(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConnManager.connectionsInfo() = %+v, want %+v", got, want)
+	}
+}